@@ -0,0 +1,63 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/go-autorest/autorest"
+)
+
+// armResourceManagerScope is the token scope requested for all ARM calls
+// made by the 'mode = "arm"' backends.
+const armResourceManagerScope = "https://management.azure.com/.default"
+
+// resolveArmAuthorizer builds the autorest.Authorizer the ARM backends
+// authenticate with, preferring (in order): an explicit client-secret
+// credential, a managed-identity credential, and finally the Azure CLI's
+// logged-in account, mirroring the precedence azidentity's own
+// DefaultAzureCredential uses.
+func resolveArmAuthorizer(c *Config) (autorest.Authorizer, error) {
+	var cred azidentity.TokenCredential
+	var err error
+
+	switch {
+	case c.ClientID != "" && c.ClientSecret != "" && c.TenantID != "":
+		cred, err = azidentity.NewClientSecretCredential(c.TenantID, c.ClientID, c.ClientSecret, nil)
+	case c.UseManagedIdentity:
+		cred, err = azidentity.NewManagedIdentityCredential(nil)
+	default:
+		cred, err = azidentity.NewAzureCLICredential(nil)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Failed to build Azure credential for ARM mode: %s", err)
+	}
+
+	return &azidentityAuthorizer{credential: cred}, nil
+}
+
+// azidentityAuthorizer adapts an azidentity.TokenCredential to the
+// autorest.Authorizer interface the 'arm/network' clients expect, since the
+// two SDK generations don't speak to each other natively.
+type azidentityAuthorizer struct {
+	credential azidentity.TokenCredential
+}
+
+func (a *azidentityAuthorizer) WithAuthorization() autorest.PrepareDecorator {
+	return func(p autorest.Preparer) autorest.Preparer {
+		return autorest.PreparerFunc(func(r *http.Request) (*http.Request, error) {
+			token, err := a.credential.GetToken(context.Background(), azidentity.TokenRequestOptions{
+				Scopes: []string{armResourceManagerScope},
+			})
+			if err != nil {
+				return nil, fmt.Errorf("Failed to acquire ARM access token: %s", err)
+			}
+			r, err = p.Prepare(r)
+			if err != nil {
+				return nil, err
+			}
+			return autorest.Prepare(r, autorest.WithBearerAuthorization(token.Token))
+		})
+	}
+}
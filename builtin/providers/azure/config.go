@@ -3,11 +3,30 @@ package azure
 import (
 	"fmt"
 	"log"
+	"math/rand"
 	"sync"
+	"time"
 
+	"github.com/Azure/azure-sdk-for-go/arm/network"
 	"github.com/Azure/azure-sdk-for-go/management"
 )
 
+// defaultOperationTimeout and defaultOperationMaxRetries are used whenever
+// the provider's 'operation_timeout'/'operation_max_retries' settings are
+// left at their zero value.
+const (
+	defaultOperationTimeout    = 10 * time.Minute
+	defaultOperationMaxRetries = 5
+)
+
+// azureModeASM and azureModeARM are the two values the provider's 'mode'
+// field accepts. ASM remains the default so that existing configurations
+// keep working against the classic Service Management API unchanged.
+const (
+	azureModeASM = "asm"
+	azureModeARM = "arm"
+)
+
 // Config is a struct which holds all the required information to access
 // Azure services.
 type Config struct {
@@ -15,6 +34,40 @@ type Config struct {
 	SubscriptionID          string
 	ManagementCert          []byte
 	ManagementUrl           string
+
+	// Environment identifies the sovereign Azure cloud the provider is
+	// targeting (e.g. AzureChinaCloud, AzureUSGovernment). Left empty, it
+	// defaults to AzurePublicCloud.
+	Environment string
+
+	// StorageEndpointSuffix overrides the resolved Environment's storage
+	// DNS suffix, for custom/Azure Stack deployments.
+	StorageEndpointSuffix string
+
+	// OperationTimeout bounds the total amount of time
+	// waitForOperationWithRetry will spend retrying a single operation.
+	OperationTimeout time.Duration
+
+	// OperationMaxRetries bounds the number of attempts
+	// waitForOperationWithRetry will make on a single operation.
+	OperationMaxRetries int
+
+	// Mode selects which backend azureClient.networkSecurityBackend talks
+	// to: azureModeASM (default) or azureModeARM.
+	Mode string
+
+	// ResourceGroupName scopes ARM-mode resources. Required when Mode is
+	// azureModeARM; ignored otherwise.
+	ResourceGroupName string
+
+	// ClientID, ClientSecret and TenantID configure an ARM-mode
+	// client-secret credential. UseManagedIdentity selects a managed
+	// identity credential instead. Leaving all of these unset falls back
+	// to the Azure CLI's logged-in account.
+	ClientID           string
+	ClientSecret       string
+	TenantID           string
+	UseManagedIdentity bool
 }
 
 // AzureClient contains all the handles required for managing Azure services.
@@ -25,6 +78,24 @@ type AzureClient struct {
 	// unfortunately; because of how Azure's network API works; doing networking operations
 	// concurrently is very hazardous, and we need a mutex.
 	mutex *sync.Mutex
+
+	// operationTimeout and operationMaxRetries govern the retry/backoff
+	// behaviour of waitForOperationWithRetry.
+	operationTimeout    time.Duration
+	operationMaxRetries int
+
+	// environment holds the resolved sovereign-cloud endpoint suffixes,
+	// used when composing resource URLs such as an instance's VHD blob URL.
+	environment azureEnvironment
+
+	// mode and resourceGroupName mirror Config.Mode/Config.ResourceGroupName.
+	mode              string
+	resourceGroupName string
+
+	// networkSecurityBackend is the ASM or ARM implementation
+	// resourceAzureSecurityGroup and resourceAzureSecurityRule dispatch to,
+	// chosen once in Config.Client() based on mode.
+	networkSecurityBackend networkSecurityBackend
 }
 
 // Client configures and returns a fully initialized Azure client.
@@ -33,31 +104,117 @@ func (c *Config) Client() (interface{}, error) {
 	var managementClient management.Client
 	var azureClient AzureClient
 
-	log.Println("[DEBUG] Building Azure management client.")
-	if c.PublishSettingsFilePath != "" {
-		managementClient, err = management.ClientFromPublishSettingsFile(
-			c.PublishSettingsFilePath,
-			c.SubscriptionID,
-		)
-	} else if c.ManagementUrl != "" {
-		managementClient, err = management.NewClientFromConfig(
-			c.SubscriptionID,
-			c.ManagementCert,
-			management.ClientConfig{c.ManagementUrl},
-		)
-	} else {
-		managementClient, err = management.NewClient(
-			c.SubscriptionID,
-			c.ManagementCert,
-		)
+	// in ARM mode the classic Service Management client is not built, so no
+	// management certificate is required. Only networkSecurityBackend is
+	// backed by ARM; resources that use managementClient directly are left
+	// without a client in that mode.
+	if c.Mode != azureModeARM {
+		log.Println("[DEBUG] Building Azure management client.")
+		if c.PublishSettingsFilePath != "" {
+			managementClient, err = management.ClientFromPublishSettingsFile(
+				c.PublishSettingsFilePath,
+				c.SubscriptionID,
+			)
+		} else if c.ManagementUrl != "" {
+			managementClient, err = management.NewClientFromConfig(
+				c.SubscriptionID,
+				c.ManagementCert,
+				management.ClientConfig{c.ManagementUrl},
+			)
+		} else {
+			managementClient, err = management.NewClient(
+				c.SubscriptionID,
+				c.ManagementCert,
+			)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Failed to create Azure management client: %s", err)
+		}
+		azureClient.managementClient = managementClient
+	}
+
+	azureClient.mutex = &sync.Mutex{}
+
+	azureClient.operationTimeout = c.OperationTimeout
+	if azureClient.operationTimeout == 0 {
+		azureClient.operationTimeout = defaultOperationTimeout
+	}
+	azureClient.operationMaxRetries = c.OperationMaxRetries
+	if azureClient.operationMaxRetries == 0 {
+		azureClient.operationMaxRetries = defaultOperationMaxRetries
 	}
+
+	azureClient.environment, err = resolveAzureEnvironment(c.Environment, c.StorageEndpointSuffix)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to create Azure management client: %s", err)
+		return nil, err
 	}
-	azureClient.managementClient = managementClient
 
-	azureClient.mutex = &sync.Mutex{}
+	azureClient.mode = c.Mode
+	if azureClient.mode == "" {
+		azureClient.mode = azureModeASM
+	}
+	azureClient.resourceGroupName = c.ResourceGroupName
+
+	switch azureClient.mode {
+	case azureModeASM:
+		azureClient.networkSecurityBackend = &asmNetworkSecurityBackend{azureClient: &azureClient}
+	case azureModeARM:
+		if azureClient.resourceGroupName == "" {
+			return nil, fmt.Errorf("'resource_group_name' is required when 'mode' is %q", azureModeARM)
+		}
+		authorizer, err := resolveArmAuthorizer(c)
+		if err != nil {
+			return nil, err
+		}
+		secGroupsClient := network.NewSecurityGroupsClient(c.SubscriptionID)
+		secGroupsClient.Authorizer = authorizer
+		secRulesClient := network.NewSecurityRulesClient(c.SubscriptionID)
+		secRulesClient.Authorizer = authorizer
+		azureClient.networkSecurityBackend = &armNetworkSecurityBackend{
+			azureClient:       &azureClient,
+			securityGroups:    secGroupsClient,
+			securityRules:     secRulesClient,
+			resourceGroupName: azureClient.resourceGroupName,
+		}
+	default:
+		return nil, fmt.Errorf("Unknown Azure provider mode %q; expected %q or %q", azureClient.mode, azureModeASM, azureModeARM)
+	}
 
 	log.Println("[DEBUG] Built Azure management client.")
 	return &azureClient, nil
 }
+
+// waitForOperationWithRetry waits for the Azure asynchronous operation
+// identified by reqID to complete, retrying on transient management errors
+// with exponential backoff and jitter, up to the client's
+// operationMaxRetries attempts or operationTimeout elapsed, whichever comes
+// first. A "resource not found" error on the operation status lookup is
+// treated as a successful terminal state, since that is what Azure returns
+// once a deleted resource's operation has been garbage-collected.
+func (c *AzureClient) waitForOperationWithRetry(reqID management.OperationID) error {
+	start := time.Now()
+	var lastErr error
+
+	for attempt := 0; attempt < c.operationMaxRetries; attempt++ {
+		if elapsed := time.Since(start); elapsed > c.operationTimeout {
+			return fmt.Errorf("Timed out after %s waiting for Azure operation %q: %s", elapsed, reqID, lastErr)
+		}
+
+		err := c.managementClient.WaitForOperation(reqID, nil)
+		if err == nil {
+			return nil
+		}
+		if management.IsResourceNotFoundError(err) {
+			log.Printf("[DEBUG] Azure operation %q's resource is gone; treating as complete.", reqID)
+			return nil
+		}
+		lastErr = err
+
+		backoff := time.Duration(1<<uint(attempt)) * 500 * time.Millisecond
+		jitter := time.Duration(rand.Int63n(int64(backoff/2) + 1))
+		log.Printf("[DEBUG] Transient error waiting for Azure operation %q (attempt %d/%d): %s", reqID, attempt+1, c.operationMaxRetries, err)
+		time.Sleep(backoff + jitter)
+	}
+
+	return fmt.Errorf("Azure operation %q did not succeed after %d retries: %s", reqID, c.operationMaxRetries, lastErr)
+}
@@ -0,0 +1,63 @@
+package azure
+
+import "fmt"
+
+// azureEnvironment describes the DNS suffix under which a sovereign Azure
+// cloud's storage endpoints (blob/queue/table) are published.
+type azureEnvironment struct {
+	Name                  string
+	StorageEndpointSuffix string
+}
+
+var (
+	azurePublicCloud = azureEnvironment{
+		Name:                  "AzurePublicCloud",
+		StorageEndpointSuffix: "core.windows.net",
+	}
+	azureChinaCloud = azureEnvironment{
+		Name:                  "AzureChinaCloud",
+		StorageEndpointSuffix: "core.chinacloudapi.cn",
+	}
+	azureUSGovernmentCloud = azureEnvironment{
+		Name:                  "AzureUSGovernment",
+		StorageEndpointSuffix: "core.usgovcloudapi.net",
+	}
+	azureGermanCloud = azureEnvironment{
+		Name:                  "AzureGermanCloud",
+		StorageEndpointSuffix: "core.cloudapi.de",
+	}
+)
+
+// azureEnvironments indexes the known sovereign clouds by the name a user
+// would set in the provider's 'environment' field.
+var azureEnvironments = map[string]azureEnvironment{
+	azurePublicCloud.Name:       azurePublicCloud,
+	azureChinaCloud.Name:        azureChinaCloud,
+	azureUSGovernmentCloud.Name: azureUSGovernmentCloud,
+	azureGermanCloud.Name:       azureGermanCloud,
+}
+
+// resolveAzureEnvironment returns the azureEnvironment matching name. An
+// empty name resolves to the public cloud. A non-empty customSuffix always
+// overrides the resolved environment's StorageEndpointSuffix, which is how
+// Azure Stack users point the provider at their own storage endpoint DNS
+// suffix without needing a named environment at all.
+func resolveAzureEnvironment(name, customSuffix string) (azureEnvironment, error) {
+	env := azurePublicCloud
+	if name != "" {
+		var ok bool
+		env, ok = azureEnvironments[name]
+		if !ok {
+			if customSuffix == "" {
+				return azureEnvironment{}, fmt.Errorf("Unknown Azure environment %q; set 'storage_endpoint_suffix' to use a custom/Azure Stack environment.", name)
+			}
+			env = azureEnvironment{Name: name}
+		}
+	}
+
+	if customSuffix != "" {
+		env.StorageEndpointSuffix = customSuffix
+	}
+
+	return env, nil
+}
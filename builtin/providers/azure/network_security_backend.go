@@ -0,0 +1,175 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/arm/network"
+	"github.com/Azure/azure-sdk-for-go/management"
+	netsecgroup "github.com/Azure/azure-sdk-for-go/management/networksecuritygroup"
+	"github.com/Azure/go-autorest/autorest/to"
+)
+
+// networkSecurityBackend abstracts the operations resourceAzureSecurityGroup
+// and resourceAzureSecurityRule need, so that their CRUD funcs can dispatch
+// to either the classic (ASM) or ARM API without knowing which one is
+// configured. Which implementation AzureClient.networkSecurityBackend holds
+// is decided once, in Config.Client(), based on the provider's 'mode' field.
+type networkSecurityBackend interface {
+	CreateSecurityGroup(name, label, location string) error
+	GetSecurityGroup(name string) (netsecgroup.NetworkSecurityGroup, error)
+	DeleteSecurityGroup(name string) error
+	SetSecurityRule(secGroupName string, rule netsecgroup.RuleSetParameters) error
+	DeleteSecurityRule(secGroupName, ruleName string) error
+}
+
+// asmNetworkSecurityBackend is the default networkSecurityBackend, talking
+// to the classic Service Management API via netsecgroup.Client.
+type asmNetworkSecurityBackend struct {
+	azureClient *AzureClient
+}
+
+func (b *asmNetworkSecurityBackend) client() netsecgroup.SecurityGroupClient {
+	return netsecgroup.NewClient(b.azureClient.managementClient)
+}
+
+func (b *asmNetworkSecurityBackend) CreateSecurityGroup(name, label, location string) error {
+	reqID, err := b.client().CreateNetworkSecurityGroup(name, label, location)
+	if err != nil {
+		return err
+	}
+	return b.azureClient.managementClient.WaitAsyncOperation(reqID)
+}
+
+func (b *asmNetworkSecurityBackend) GetSecurityGroup(name string) (netsecgroup.NetworkSecurityGroup, error) {
+	return b.client().GetNetworkSecurityGroup(name)
+}
+
+func (b *asmNetworkSecurityBackend) DeleteSecurityGroup(name string) error {
+	reqID, err := b.client().DeleteNetworkSecurityGroup(name)
+	if err != nil {
+		return err
+	}
+	return b.azureClient.managementClient.WaitAsyncOperation(reqID)
+}
+
+func (b *asmNetworkSecurityBackend) SetSecurityRule(secGroupName string, rule netsecgroup.RuleSetParameters) error {
+	reqID, err := b.client().SetNetworkSecurityGroupRule(secGroupName, rule)
+	if err != nil {
+		return err
+	}
+	return b.azureClient.waitForOperationWithRetry(reqID)
+}
+
+func (b *asmNetworkSecurityBackend) DeleteSecurityRule(secGroupName, ruleName string) error {
+	reqID, err := b.client().DeleteNetworkSecurityGroupRule(secGroupName, ruleName)
+	if err != nil {
+		return err
+	}
+	return b.azureClient.waitForOperationWithRetry(reqID)
+}
+
+// armNetworkSecurityBackend is the opt-in networkSecurityBackend selected by
+// setting 'mode = "arm"' on the provider, talking to the Azure Resource
+// Manager API via network.SecurityGroupsClient/SecurityRulesClient, scoped
+// to the provider's 'resource_group_name'.
+type armNetworkSecurityBackend struct {
+	azureClient       *AzureClient
+	securityGroups    network.SecurityGroupsClient
+	securityRules     network.SecurityRulesClient
+	resourceGroupName string
+}
+
+func (b *armNetworkSecurityBackend) CreateSecurityGroup(name, label, location string) error {
+	future, err := b.securityGroups.CreateOrUpdate(context.Background(), b.resourceGroupName, name, network.SecurityGroup{
+		Name:     to.StringPtr(name),
+		Location: to.StringPtr(location),
+		Tags:     map[string]*string{"label": to.StringPtr(label)},
+	})
+	if err != nil {
+		return fmt.Errorf("Error creating ARM network security group %q: %s", name, err)
+	}
+	return future.WaitForCompletionRef(context.Background(), b.securityGroups.Client)
+}
+
+func (b *armNetworkSecurityBackend) GetSecurityGroup(name string) (netsecgroup.NetworkSecurityGroup, error) {
+	nsg, err := b.securityGroups.Get(context.Background(), b.resourceGroupName, name, "")
+	if err != nil {
+		if nsg.StatusCode == 404 {
+			return netsecgroup.NetworkSecurityGroup{}, management.ResourceNotFoundError{}
+		}
+		return netsecgroup.NetworkSecurityGroup{}, err
+	}
+
+	result := netsecgroup.NetworkSecurityGroup{Name: name}
+	if nsg.SecurityRules != nil {
+		for _, rule := range *nsg.SecurityRules {
+			result.Rules = append(result.Rules, armSecurityRuleToASM(rule))
+		}
+	}
+	return result, nil
+}
+
+func (b *armNetworkSecurityBackend) DeleteSecurityGroup(name string) error {
+	future, err := b.securityGroups.Delete(context.Background(), b.resourceGroupName, name)
+	if err != nil {
+		return fmt.Errorf("Error deleting ARM network security group %q: %s", name, err)
+	}
+	return future.WaitForCompletionRef(context.Background(), b.securityGroups.Client)
+}
+
+func (b *armNetworkSecurityBackend) SetSecurityRule(secGroupName string, rule netsecgroup.RuleSetParameters) error {
+	future, err := b.securityRules.CreateOrUpdate(context.Background(), b.resourceGroupName, secGroupName, rule.Name, network.SecurityRule{
+		Name: to.StringPtr(rule.Name),
+		SecurityRulePropertiesFormat: &network.SecurityRulePropertiesFormat{
+			Protocol:                 network.SecurityRuleProtocol(rule.Protocol),
+			SourcePortRange:          to.StringPtr(rule.SourcePortRange),
+			DestinationPortRange:     to.StringPtr(rule.DestinationPortRange),
+			SourceAddressPrefix:      to.StringPtr(rule.SourceAddressPrefix),
+			DestinationAddressPrefix: to.StringPtr(rule.DestinationAddressPrefix),
+			Access:                   network.SecurityRuleAccess(rule.Action),
+			Priority:                 to.Int32Ptr(int32(rule.Priority)),
+			Direction:                network.SecurityRuleDirection(rule.Type),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("Error setting ARM network security rule %q: %s", rule.Name, err)
+	}
+	return future.WaitForCompletionRef(context.Background(), b.securityRules.Client)
+}
+
+func (b *armNetworkSecurityBackend) DeleteSecurityRule(secGroupName, ruleName string) error {
+	future, err := b.securityRules.Delete(context.Background(), b.resourceGroupName, secGroupName, ruleName)
+	if err != nil {
+		return fmt.Errorf("Error deleting ARM network security rule %q: %s", ruleName, err)
+	}
+	return future.WaitForCompletionRef(context.Background(), b.securityRules.Client)
+}
+
+// armSecurityRuleToASM adapts an ARM network.SecurityRule into the classic
+// netsecgroup.Rule shape, so that resourceAzureSecurityGroup's Read/Update
+// reconciliation logic works unmodified regardless of the backend in use.
+func armSecurityRuleToASM(rule network.SecurityRule) netsecgroup.Rule {
+	asmRule := netsecgroup.Rule{Name: *rule.Name}
+	if props := rule.SecurityRulePropertiesFormat; props != nil {
+		asmRule.Protocol = string(props.Protocol)
+		asmRule.Action = string(props.Access)
+		asmRule.Type = string(props.Direction)
+		if props.Priority != nil {
+			asmRule.Priority = int(*props.Priority)
+		}
+		if props.SourcePortRange != nil {
+			asmRule.SourcePortRange = *props.SourcePortRange
+		}
+		if props.DestinationPortRange != nil {
+			asmRule.DestinationPortRange = *props.DestinationPortRange
+		}
+		if props.SourceAddressPrefix != nil {
+			asmRule.SourceAddressPrefix = *props.SourceAddressPrefix
+		}
+		if props.DestinationAddressPrefix != nil {
+			asmRule.DestinationAddressPrefix = *props.DestinationAddressPrefix
+		}
+	}
+	return asmRule
+}
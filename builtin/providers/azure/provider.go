@@ -2,6 +2,7 @@ package azure
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
@@ -34,19 +35,82 @@ func Provider() terraform.ResourceProvider {
 				Default:     "",
 				Description: parameterDescriptions["management_url"],
 			},
+			"environment": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: parameterDescriptions["environment"],
+			},
+			"storage_endpoint_suffix": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: parameterDescriptions["storage_endpoint_suffix"],
+			},
+			"mode": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     azureModeASM,
+				Description: parameterDescriptions["mode"],
+			},
+			"resource_group_name": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: parameterDescriptions["resource_group_name"],
+			},
+			"client_id": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: parameterDescriptions["client_id"],
+			},
+			"client_secret": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: parameterDescriptions["client_secret"],
+			},
+			"tenant_id": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: parameterDescriptions["tenant_id"],
+			},
+			"use_managed_identity": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: parameterDescriptions["use_managed_identity"],
+			},
+			"operation_timeout": &schema.Schema{
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     int(defaultOperationTimeout / time.Second),
+				Description: parameterDescriptions["operation_timeout"],
+			},
+			"operation_max_retries": &schema.Schema{
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     defaultOperationMaxRetries,
+				Description: parameterDescriptions["operation_max_retries"],
+			},
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
-			"azure_instance":                 resourceAzureInstance(),
-			"azure_hosted_service":           resourceAzureHostedService(),
-			"azure_storage_service":          resourceAzureStorageService(),
-			"azure_storage_container":        resourceAzureStorageContainer(),
-			"azure_storage_blob":             resourceAzureStorageBlob(),
-			"azure_virtual_network":          resourceAzureVirtualNetwork(),
-			"azure_dns_server":               resourceAzureDnsServer(),
-			"azure_local_network_connection": resourceAzureLocalNetworkConnection(),
-			"azure_security_group":           resourceAzureSecurityGroup(),
-			"azure_security_group_rule":      resourceAzureSecurityGroupRule(),
+			"azure_instance":                   resourceAzureInstance(),
+			"azure_hosted_service":             resourceAzureHostedService(),
+			"azure_storage_service":            resourceAzureStorageService(),
+			"azure_storage_container":          resourceAzureStorageContainer(),
+			"azure_storage_blob":               resourceAzureStorageBlob(),
+			"azure_virtual_network":            resourceAzureVirtualNetwork(),
+			"azure_subnet":                     resourceAzureSubnet(),
+			"azure_dns_server":                 resourceAzureDnsServer(),
+			"azure_local_network_connection":   resourceAzureLocalNetworkConnection(),
+			"azure_security_group":             resourceAzureSecurityGroup(),
+			"azure_security_group_rule":        resourceAzureSecurityGroupRule(),
+			"azure_security_rule":              resourceAzureSecurityRule(),
+			"azure_security_group_association": resourceAzureSecurityGroupAssociation(),
 		},
 
 		ConfigureFunc: providerConfigure,
@@ -64,6 +128,16 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 		SubscriptionID:          d.Get("subscription_id").(string),
 		ManagementCert:          []byte(d.Get("management_certificate").(string)),
 		ManagementUrl:           d.Get("management_url").(string),
+		Environment:             d.Get("environment").(string),
+		StorageEndpointSuffix:   d.Get("storage_endpoint_suffix").(string),
+		OperationTimeout:        time.Duration(d.Get("operation_timeout").(int)) * time.Second,
+		OperationMaxRetries:     d.Get("operation_max_retries").(int),
+		Mode:                    d.Get("mode").(string),
+		ResourceGroupName:       d.Get("resource_group_name").(string),
+		ClientID:                d.Get("client_id").(string),
+		ClientSecret:            d.Get("client_secret").(string),
+		TenantID:                d.Get("tenant_id").(string),
+		UseManagedIdentity:      d.Get("use_managed_identity").(bool),
 	}
 
 	return config.Client()
@@ -72,11 +146,18 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 // areValidAzureSettings checks whether the provided dataset contains all the
 // necessary fields for accessing the Azure management API.
 func areValidAzureSettings(d *schema.ResourceData) bool {
+	_, subIdOk := d.GetOk("subscription_id")
+
+	// ARM mode authenticates via azidentity instead of a management
+	// certificate, so only the subscription ID is required up front.
+	if d.Get("mode").(string) == azureModeARM {
+		return subIdOk
+	}
+
 	if _, ok := d.GetOk("publish_settings_file"); ok {
 		return true
 	}
 
-	_, subIdOk := d.GetOk("subscription_id")
 	_, certOk := d.GetOk("management_certificate")
 
 	return subIdOk && certOk
@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"log"
 
+	"github.com/Azure/azure-sdk-for-go/management"
 	"github.com/Azure/azure-sdk-for-go/management/virtualnetwork"
+	"github.com/hashicorp/terraform/helper/azureid"
 	"github.com/hashicorp/terraform/helper/schema"
 )
 
@@ -47,6 +49,7 @@ func resourceAzureDnsServerCreate(d *schema.ResourceData, meta interface{}) erro
 
 	log.Println("[INFO] Fetching current network configuration from Azure.")
 	azureClient.mutex.Lock()
+	defer azureClient.mutex.Unlock()
 	netConf, err := networkClient.GetVirtualNetworkConfiguration()
 	if err != nil {
 		return fmt.Errorf("Failed to get the current network configuration from Azure: %s", err)
@@ -64,13 +67,18 @@ func resourceAzureDnsServerCreate(d *schema.ResourceData, meta interface{}) erro
 
 	// send the configuration back to Azure:
 	log.Println("[INFO] Sending updated network configuration back to Azure.")
-	err = networkClient.SetVirtualNetworkConfiguration(netConf)
-	azureClient.mutex.Unlock()
+	reqID, err := networkClient.SetVirtualNetworkConfiguration(netConf)
 	if err != nil {
 		return fmt.Errorf("Failed setting updated network configuration: %s", err)
 	}
+	if err := azureClient.waitForOperationWithRetry(reqID); err != nil {
+		return fmt.Errorf("Failed updating the network configuration: %s", err)
+	}
 
-	d.SetId(getRandomStringLabel(50))
+	// derive the ID from the DNS server's natural key so that re-reading a
+	// resource that drifted out-of-band recovers the same ID it was created
+	// with, instead of minting a fresh random one.
+	d.SetId(azureid.NewDeterministicID("dns-server", name+address))
 	return nil
 }
 
@@ -87,6 +95,12 @@ func resourceAzureDnsServerRead(d *schema.ResourceData, meta interface{}) error
 	log.Println("[INFO] Fetching current network configuration from Azure.")
 	netConf, err := networkClient.GetVirtualNetworkConfiguration()
 	if err != nil {
+		if management.IsResourceNotFoundError(err) {
+			// the network configuration itself has been deleted in the
+			// meantime, so our DNS server definition is gone along with it.
+			d.SetId("")
+			return nil
+		}
 		return fmt.Errorf("Failed to get the current network configuration from Azure: %s", err)
 	}
 
@@ -119,43 +133,42 @@ func resourceAzureDnsServerUpdate(d *schema.ResourceData, meta interface{}) erro
 	managementClient := azureClient.managementClient
 	networkClient := virtualnetwork.NewClient(managementClient)
 
-	var err error
-	var found bool
 	name := d.Get("name").(string)
-	caddress := d.HasChange("dns_address")
-	var netConf virtualnetwork.NetworkConfiguration
-
-	if caddress {
-		log.Println("[DEBUG] DNS server address has changes; updating it on Azure.")
-		log.Println("[INFO] Fetching current network configuration from Azure.")
-		azureClient.mutex.Lock()
-		netConf, err = networkClient.GetVirtualNetworkConfiguration()
-		if err != nil {
-			return fmt.Errorf("Failed to get the current network configuration from Azure: %s", err)
-		}
 
-		// search for our DNS and update its address value:
-		for i, dns := range netConf.Configuration.Dns.DnsServers {
-			found = true
-			if dns.Name == name {
-				netConf.Configuration.Dns.DnsServers[i].IPAddress = d.Get("dns_address").(string)
-			}
-		}
+	if !d.HasChange("dns_address") {
+		return nil
+	}
 
-		// if the config has changes, send the configuration back to Azure:
-		if found && caddress {
-			log.Println("[INFO] Sending updated network configuration back to Azure.")
-			err = networkClient.SetVirtualNetworkConfiguration(netConf)
-			azureClient.mutex.Unlock()
-			if err != nil {
-				return fmt.Errorf("Failed setting updated network configuration: %s", err)
-			}
-		}
+	log.Println("[DEBUG] DNS server address has changed; updating it on Azure.")
+	log.Println("[INFO] Fetching current network configuration from Azure.")
+	azureClient.mutex.Lock()
+	defer azureClient.mutex.Unlock()
+	netConf, err := networkClient.GetVirtualNetworkConfiguration()
+	if err != nil {
+		return fmt.Errorf("Failed to get the current network configuration from Azure: %s", err)
 	}
 
-	// remove the resource from the state if it has been deleted in the meantime:
+	// search for our DNS and update its address value:
+	var found bool
+	for i, dns := range netConf.Configuration.Dns.DnsServers {
+		if dns.Name == name {
+			found = true
+			netConf.Configuration.Dns.DnsServers[i].IPAddress = d.Get("dns_address").(string)
+		}
+	}
 	if !found {
+		// the DNS server has been deleted out-of-band in the meantime.
 		d.SetId("")
+		return nil
+	}
+
+	log.Println("[INFO] Sending updated network configuration back to Azure.")
+	reqID, err := networkClient.SetVirtualNetworkConfiguration(netConf)
+	if err != nil {
+		return fmt.Errorf("Failed setting updated network configuration: %s", err)
+	}
+	if err := azureClient.waitForOperationWithRetry(reqID); err != nil {
+		return fmt.Errorf("Failed updating the network configuration: %s", err)
 	}
 
 	return nil
@@ -201,6 +214,7 @@ func resourceAzureDnsServerDelete(d *schema.ResourceData, meta interface{}) erro
 
 	log.Println("[INFO] Fetching current network configuration from Azure.")
 	azureClient.mutex.Lock()
+	defer azureClient.mutex.Unlock()
 	netConf, err := networkClient.GetVirtualNetworkConfiguration()
 	if err != nil {
 		return fmt.Errorf("Failed to get the current network configuration from Azure: %s", err)
@@ -220,11 +234,13 @@ func resourceAzureDnsServerDelete(d *schema.ResourceData, meta interface{}) erro
 
 	// send the configuration back to Azure:
 	log.Println("[INFO] Sending updated network configuration back to Azure.")
-	err = networkClient.SetVirtualNetworkConfiguration(netConf)
-	azureClient.mutex.Unlock()
+	reqID, err := networkClient.SetVirtualNetworkConfiguration(netConf)
 	if err != nil {
 		return fmt.Errorf("Failed setting updated network configuration: %s", err)
 	}
+	if err := azureClient.waitForOperationWithRetry(reqID); err != nil {
+		return fmt.Errorf("Failed updating the network configuration: %s", err)
+	}
 
 	d.SetId("")
 	return nil
@@ -6,7 +6,9 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/management"
 	"github.com/Azure/azure-sdk-for-go/management/hostedservice"
+	"github.com/hashicorp/terraform/helper/azureid"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
 )
 
 // resourceAzureHostedService returns the schema.Resource associated to an
@@ -19,6 +21,7 @@ func resourceAzureHostedService() *schema.Resource {
 		Delete: resourceAzureHostedServiceDelete,
 
 		SchemaVersion: 1,
+		MigrateState:  resourceAzureHostedServiceMigrateState,
 
 		Schema: map[string]*schema.Schema{
 			"service_name": &schema.Schema{
@@ -71,6 +74,40 @@ func resourceAzureHostedService() *schema.Resource {
 	}
 }
 
+// resourceAzureHostedServiceMigrateState upgrades the on-disk state of a
+// resourceAzureHostedService from an older schema version to the current
+// one, dispatching on the recorded source version.
+func resourceAzureHostedServiceMigrateState(v int, is *terraform.InstanceState, meta interface{}) (*terraform.InstanceState, error) {
+	switch v {
+	case 0:
+		log.Println("[INFO] Found Azure Hosted Service state v0; migrating to v1.")
+		return migrateAzureHostedServiceStateV0toV1(is)
+	default:
+		return is, fmt.Errorf("Unexpected schema version for Azure Hosted Service state: %d", v)
+	}
+}
+
+// migrateAzureHostedServiceStateV0toV1 renames the pre-1.0 'label'
+// attribute, which used to be tracked as a regular attribute, to instead
+// serve as the resource's ID, matching the convention established by
+// resourceAzureHostedServiceCreate.
+func migrateAzureHostedServiceStateV0toV1(is *terraform.InstanceState) (*terraform.InstanceState, error) {
+	if is == nil || len(is.Attributes) == 0 {
+		log.Println("[DEBUG] Empty Azure Hosted Service state; nothing to migrate.")
+		return is, nil
+	}
+
+	log.Printf("[DEBUG] Azure Hosted Service attributes before migration: %#v", is.Attributes)
+
+	if label, ok := is.Attributes["label"]; ok && label != "" {
+		is.ID = label
+		delete(is.Attributes, "label")
+	}
+
+	log.Printf("[DEBUG] Azure Hosted Service attributes after migration: %#v", is.Attributes)
+	return is, nil
+}
+
 // resourceAzureHostedServiceCreate does all the necessary API calls
 // to create a hosted service on Azure.
 func resourceAzureHostedServiceCreate(d *schema.ResourceData, meta interface{}) error {
@@ -87,7 +124,7 @@ func resourceAzureHostedServiceCreate(d *schema.ResourceData, meta interface{})
 	description := d.Get("description").(string)
 
 	// set the label as the resource's ID:
-	label := getRandomStringLabel(20)
+	label := azureid.NewUniqueID("hosted-service")
 	d.SetId(label)
 
 	err := hostedServiceClient.CreateHostedService(
@@ -185,7 +222,7 @@ func resourceAzureHostedServiceDelete(d *schema.ResourceData, meta interface{})
 	}
 
 	log.Println("[DEBUG] Awaiting confirmation on hosted service deletion.")
-	err = managementClient.WaitForOperation(reqID, nil)
+	err = azureClient.waitForOperationWithRetry(reqID)
 	if err != nil {
 		return fmt.Errorf("Error on hosted service deletion: %s", err)
 	}
@@ -3,11 +3,14 @@ package azure
 import (
 	"fmt"
 	"log"
+	"reflect"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/management"
 	"github.com/Azure/azure-sdk-for-go/management/hostedservice"
 	"github.com/Azure/azure-sdk-for-go/management/virtualmachine"
 	"github.com/Azure/azure-sdk-for-go/management/vmutils"
+	"github.com/hashicorp/terraform/helper/azureid"
 	"github.com/hashicorp/terraform/helper/schema"
 )
 
@@ -69,11 +72,21 @@ func resourceAzureInstance() *schema.Resource {
 				ForceNew:    true,
 				Description: parameterDescriptions["location"],
 			},
-			// TODO(aznashwan): improve storage disk mechanism.
-			// 	- existing disk image
-			//	- arbitrary remote image
-			// 	- add existing data disk
-			// 	- add new data disk
+			"virtual_network": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"subnet": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"static_ip": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
 			"storage_account": &schema.Schema{
 				Type:        schema.TypeString,
 				Required:    true,
@@ -86,6 +99,71 @@ func resourceAzureInstance() *schema.Resource {
 				ForceNew:    true,
 				Description: parameterDescriptions["storage_container"],
 			},
+			"os_disk": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						// one of "platform_image" (the default; deploys the
+						// "image" attribute as a fresh platform image),
+						// "existing_disk" (reuses an already-registered OS
+						// disk) or "remote_image" (captures an arbitrary
+						// remote VHD as a user/published VM image first).
+						"mode": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+							Default:  "platform_image",
+						},
+						"disk_name": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"source_image_link": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						// only consulted in "remote_image" mode: whether
+						// source_image_link refers to a user-captured VM
+						// image or a publisher-published one.
+						"image_type": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+							Default:  "user",
+						},
+					},
+				},
+			},
+			"data_disk": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"lun": &schema.Schema{
+							Type:     schema.TypeInt,
+							Required: true,
+						},
+						"size_gb": &schema.Schema{
+							Type:     schema.TypeInt,
+							Required: true,
+						},
+						"caching": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+							Default:  "None",
+						},
+						"media_link": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"source_media_link": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+					},
+				},
+			},
 			// login attributes:
 			"user_name": &schema.Schema{
 				Type:        schema.TypeString,
@@ -97,11 +175,108 @@ func resourceAzureInstance() *schema.Resource {
 				Optional:    true,
 				Description: parameterDescriptions["user_password"],
 			},
-			// "ssh_thumbprints": &schema.Schema{
-			// Type:     schema.TypeList,
-			// Optional: true,
-			// Description: parameterDescriptions["ssh_thumbprints"],
-			// },
+			"linux_config": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"ssh_keys": &schema.Schema{
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"fingerprint": &schema.Schema{
+										Type:     schema.TypeString,
+										Required: true,
+									},
+									"path": &schema.Schema{
+										Type:     schema.TypeString,
+										Required: true,
+									},
+								},
+							},
+						},
+						"custom_data": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+					},
+				},
+			},
+			"windows_config": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"admin_username": &schema.Schema{
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"admin_password": &schema.Schema{
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"computer_name": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"enable_automatic_updates": &schema.Schema{
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  true,
+						},
+						"timezone": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"winrm_listener": &schema.Schema{
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"protocol": &schema.Schema{
+										Type:     schema.TypeString,
+										Required: true,
+									},
+									"certificate_thumbprint": &schema.Schema{
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+								},
+							},
+						},
+						"domain_join": &schema.Schema{
+							Type:     schema.TypeList,
+							Optional: true,
+							MaxItems: 1,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"domain": &schema.Schema{
+										Type:     schema.TypeString,
+										Required: true,
+									},
+									"ou": &schema.Schema{
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+									"username": &schema.Schema{
+										Type:     schema.TypeString,
+										Required: true,
+									},
+									"password": &schema.Schema{
+										Type:     schema.TypeString,
+										Required: true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
 			//
 			// computed attributes:
 			"status": &schema.Schema{
@@ -133,13 +308,269 @@ func resourceAzureInstance() *schema.Resource {
 					Computed: true,
 				},
 			},
+			"endpoint": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": &schema.Schema{
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"protocol": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+							Default:  "tcp",
+						},
+						"public_port": &schema.Schema{
+							Type:     schema.TypeInt,
+							Required: true,
+						},
+						"private_port": &schema.Schema{
+							Type:     schema.TypeInt,
+							Required: true,
+						},
+						"load_balanced_endpoint_set_name": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"load_balancer_probe": &schema.Schema{
+							Type:     schema.TypeList,
+							Optional: true,
+							MaxItems: 1,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"path": &schema.Schema{
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+									"port": &schema.Schema{
+										Type:     schema.TypeInt,
+										Required: true,
+									},
+									"protocol": &schema.Schema{
+										Type:     schema.TypeString,
+										Optional: true,
+										Default:  "tcp",
+									},
+									"interval_in_seconds": &schema.Schema{
+										Type:     schema.TypeInt,
+										Optional: true,
+										Default:  15,
+									},
+									"timeout_in_seconds": &schema.Schema{
+										Type:     schema.TypeInt,
+										Optional: true,
+										Default:  31,
+									},
+								},
+							},
+						},
+						"acl": &schema.Schema{
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"order": &schema.Schema{
+										Type:     schema.TypeInt,
+										Required: true,
+									},
+									"action": &schema.Schema{
+										Type:     schema.TypeString,
+										Optional: true,
+										Default:  "permit",
+									},
+									"remote_subnet": &schema.Schema{
+										Type:     schema.TypeString,
+										Required: true,
+									},
+									"description": &schema.Schema{
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
 			// TODO(aznashwan):
-			// 	- configure with external ports
 			//	- use virtualnetwork package where applicable.
 		},
 	}
 }
 
+// configureInstanceOSDisk configures the role's OS disk according to the
+// mode selected in the 'os_disk' block: a fresh platform image (the
+// default), an already-registered OS disk, or an arbitrary remote image
+// captured as a user/published VM image.
+func configureInstanceOSDisk(d *schema.ResourceData, role *virtualmachine.Role, image, vhdURL, label string) error {
+	osDisks := d.Get("os_disk").([]interface{})
+	if len(osDisks) == 0 {
+		return vmutils.ConfigureDeploymentFromPlatformImage(role, image, vhdURL, label)
+	}
+
+	osDisk := osDisks[0].(map[string]interface{})
+	switch mode := osDisk["mode"].(string); mode {
+	case "", "platform_image":
+		return vmutils.ConfigureDeploymentFromPlatformImage(role, image, vhdURL, label)
+	case "existing_disk":
+		diskName := osDisk["disk_name"].(string)
+		return vmutils.ConfigureDeploymentFromExistingOSDisk(role, diskName, label)
+	case "remote_image":
+		sourceImageLink := osDisk["source_image_link"].(string)
+		if osDisk["image_type"].(string) == "published" {
+			return vmutils.ConfigureDeploymentFromPublishedVMImage(role, sourceImageLink, label)
+		}
+		return vmutils.ConfigureDeploymentFromUserImage(role, sourceImageLink, label)
+	default:
+		return fmt.Errorf("Unknown os_disk mode: %q", mode)
+	}
+}
+
+// configureInstanceGuestOS branches on the 'os_type' field and applies
+// either the 'windows_config' or 'linux_config' block (falling back to the
+// legacy top-level 'user_name'/'user_password' with public SSH for Linux,
+// for backwards compatibility with configurations written before those
+// blocks existed).
+func configureInstanceGuestOS(d *schema.ResourceData, role *virtualmachine.Role, name string) error {
+	userName := d.Get("user_name").(string)
+	userPass := d.Get("user_password").(string)
+
+	if strings.EqualFold(d.Get("os_type").(string), "windows") {
+		configs := d.Get("windows_config").([]interface{})
+		if len(configs) == 0 {
+			vmutils.ConfigureForWindows(role, name, userName, userPass, true, "")
+			return nil
+		}
+
+		win := configs[0].(map[string]interface{})
+		vmutils.ConfigureForWindows(
+			role,
+			name,
+			win["admin_username"].(string),
+			win["admin_password"].(string),
+			win["enable_automatic_updates"].(bool),
+			win["timezone"].(string),
+		)
+
+		for _, v := range win["winrm_listener"].([]interface{}) {
+			listener := v.(map[string]interface{})
+			switch listener["protocol"].(string) {
+			case "https":
+				if err := vmutils.ConfigureWinRMOverHTTPS(role, name, listener["certificate_thumbprint"].(string)); err != nil {
+					return fmt.Errorf("Failed to configure WinRM over HTTPS: %s", err)
+				}
+			default:
+				if err := vmutils.ConfigureWinRMOverHTTP(role, name); err != nil {
+					return fmt.Errorf("Failed to configure WinRM over HTTP: %s", err)
+				}
+			}
+		}
+
+		if joins := win["domain_join"].([]interface{}); len(joins) > 0 {
+			join := joins[0].(map[string]interface{})
+			vmutils.ConfigureWithDomain(
+				role,
+				join["username"].(string),
+				join["password"].(string),
+				join["domain"].(string),
+				join["ou"].(string),
+			)
+		}
+
+		return nil
+	}
+
+	configs := d.Get("linux_config").([]interface{})
+	if len(configs) == 0 {
+		vmutils.ConfigureForLinux(role, name, userName, userPass)
+		vmutils.ConfigureWithPublicSSH(role)
+		return nil
+	}
+
+	linux := configs[0].(map[string]interface{})
+	vmutils.ConfigureForLinux(role, name, userName, userPass)
+
+	if keys := linux["ssh_keys"].([]interface{}); len(keys) > 0 {
+		for _, v := range keys {
+			key := v.(map[string]interface{})
+			if err := vmutils.ConfigureWithSSH(role, key["fingerprint"].(string), key["path"].(string)); err != nil {
+				return fmt.Errorf("Failed to configure SSH key: %s", err)
+			}
+		}
+	} else {
+		vmutils.ConfigureWithPublicSSH(role)
+	}
+
+	if customData := linux["custom_data"].(string); customData != "" {
+		if err := vmutils.ConfigureWithCustomData(role, customData); err != nil {
+			return fmt.Errorf("Failed to configure custom data: %s", err)
+		}
+	}
+
+	return nil
+}
+
+// configureInstanceEndpoints adds every 'endpoint' block on d to role as an
+// external port, attaching its load balancer probe and ACL rules (if any).
+func configureInstanceEndpoints(d *schema.ResourceData, role *virtualmachine.Role) error {
+	endpoints := d.Get("endpoint").([]interface{})
+	for i, v := range endpoints {
+		endpoint := v.(map[string]interface{})
+		if err := configureInstanceEndpoint(role, endpoint); err != nil {
+			return fmt.Errorf("Failed to configure endpoint %d: %s", i+1, err)
+		}
+	}
+
+	return nil
+}
+
+// configureInstanceEndpoint adds a single endpoint to role, along with its
+// optional load balancer probe and ACL rules.
+func configureInstanceEndpoint(role *virtualmachine.Role, endpoint map[string]interface{}) error {
+	name := endpoint["name"].(string)
+	protocol := endpoint["protocol"].(string)
+	publicPort := endpoint["public_port"].(int)
+	privatePort := endpoint["private_port"].(int)
+
+	if err := vmutils.ConfigureWithExternalPort(role, name, privatePort, publicPort, protocol); err != nil {
+		return err
+	}
+
+	if probes := endpoint["load_balancer_probe"].([]interface{}); len(probes) > 0 {
+		probe := probes[0].(map[string]interface{})
+		if err := vmutils.ConfigureWithExternalLBEndpoint(
+			role,
+			name,
+			endpoint["load_balanced_endpoint_set_name"].(string),
+			probe["path"].(string),
+			probe["port"].(int),
+			probe["protocol"].(string),
+			probe["interval_in_seconds"].(int),
+			probe["timeout_in_seconds"].(int),
+		); err != nil {
+			return fmt.Errorf("Failed to configure load balancer probe: %s", err)
+		}
+	}
+
+	for _, v := range endpoint["acl"].([]interface{}) {
+		acl := v.(map[string]interface{})
+		if err := vmutils.ConfigureWithSecurityGroup(
+			role,
+			name,
+			acl["order"].(int),
+			acl["action"].(string),
+			acl["remote_subnet"].(string),
+			acl["description"].(string),
+		); err != nil {
+			return fmt.Errorf("Failed to configure ACL rule: %s", err)
+		}
+	}
+
+	return nil
+}
+
 // resourceAzureInstanceCreate does all the necessary API calls to create the
 // configuration and deploy the Azure instance.
 // TODO(aznashwan): use vmutils.WaitForDeploymentPowerState.
@@ -151,7 +582,7 @@ func resourceAzureInstanceCreate(d *schema.ResourceData, meta interface{}) error
 	managementClient := azureClient.managementClient
 
 	// general variables:
-	label := getRandomStringLabel(50)
+	label := azureid.NewUniqueID("instance")
 	d.SetId(label)
 	image := d.Get("image").(string)
 	name := d.Get("name").(string)
@@ -186,27 +617,57 @@ func resourceAzureInstanceCreate(d *schema.ResourceData, meta interface{}) error
 	role := vmutils.NewVMConfiguration(name, d.Get("size").(string))
 
 	// configure the VM's storage:
-	// TODO(aznashwan): put things right here:
 	storAccount := d.Get("storage_account").(string)
 	storContainer := d.Get("storage_container").(string)
-	vhdURL := fmt.Sprintf("http://%s.blob.core.windows.net/%s/%s.vhd", storAccount, storContainer, name)
+	vhdURL := fmt.Sprintf("http://%s.blob.%s/%s/%s.vhd", storAccount, azureClient.environment.StorageEndpointSuffix, storContainer, name)
 
-	err = vmutils.ConfigureDeploymentFromPlatformImage(&role, image, vhdURL, label)
+	err = configureInstanceOSDisk(d, &role, image, vhdURL, label)
 	if err != nil {
 		return fmt.Errorf("Failed to configure deployment: %s", err)
 	}
 
-	// configure VM details:
-	userName := d.Get("user_name").(string)
-	userPass := d.Get("user_password").(string)
-	vmutils.ConfigureForLinux(&role, name, userName, userPass)
-	vmutils.ConfigureWithPublicSSH(&role)
+	// attach any configured data disks:
+	if ndisks := d.Get("data_disk.#").(int); ndisks > 0 {
+		for i := 0; i < ndisks; i++ {
+			disk := d.Get(fmt.Sprintf("data_disk.%d", i)).(map[string]interface{})
+			lun := disk["lun"].(int)
+			sizeGB := disk["size_gb"].(int)
+			if err := vmutils.AppendDataDisk(&role, lun, sizeGB, label); err != nil {
+				return fmt.Errorf("Failed to configure data disk %d: %s", i+1, err)
+			}
+			applyDataDiskOverrides(&role, lun, disk)
+		}
+	}
+
+	// configure VM guest OS details:
+	if err := configureInstanceGuestOS(d, &role, name); err != nil {
+		return fmt.Errorf("Failed to configure guest OS: %s", err)
+	}
+
+	// configure any exposed endpoints:
+	if err := configureInstanceEndpoints(d, &role); err != nil {
+		return fmt.Errorf("Failed to configure endpoints: %s", err)
+	}
+
+	// place the VM on the given virtual network/subnet, if requested; this
+	// is serialized through the client's mutex since concurrent networking
+	// operations against Azure are hazardous.
+	if subnet := d.Get("subnet").(string); subnet != "" {
+		azureClient.mutex.Lock()
+		vmutils.ConfigureWithSubnet(&role, subnet)
+		if staticIP := d.Get("static_ip").(string); staticIP != "" {
+			vmutils.ConfigureWithStaticIP(&role, staticIP)
+		}
+		azureClient.mutex.Unlock()
+	}
 
 	// deploy the VM:
 	reqID, err := virtualmachine.NewClient(managementClient).CreateDeployment(
 		role,
 		serviceName,
-		virtualmachine.CreateDeploymentOptions{},
+		virtualmachine.CreateDeploymentOptions{
+			VirtualNetworkName: d.Get("virtual_network").(string),
+		},
 	)
 	if err != nil {
 		return fmt.Errorf("Failed to initiate deployment creation: %s", err)
@@ -243,7 +704,7 @@ func resourceAzureInstanceRead(d *schema.ResourceData, meta interface{}) error {
 		if role.InstanceName == name {
 			d.Set("status", role.InstanceStatus)
 			d.Set("power_state", role.PowerState)
-			// d.Set("private_ip", role.IpAddress)
+			d.Set("private_ip", role.IpAddress)
 			d.Set("host_name", role.HostName)
 			d.Set("agent_status", role.GuestAgentStatus)
 
@@ -260,7 +721,184 @@ func resourceAzureInstanceRead(d *schema.ResourceData, meta interface{}) error {
 
 // resourceAzureInstanceUpdate does all the necessary API calls to update
 // the configuration of an instance deployed on Azure.
+// Changes to 'size', 'data_disk' and 'endpoint' are applied in place via
+// UpdateRole; changes to any other non-ForceNew field are not sent to Azure.
 func resourceAzureInstanceUpdate(d *schema.ResourceData, meta interface{}) error {
+	csize := d.HasChange("size")
+	cdisks := d.HasChange("data_disk")
+	cendpoints := d.HasChange("endpoint")
+	if !csize && !cdisks && !cendpoints {
+		return nil
+	}
+
+	azureClient, ok := meta.(*AzureClient)
+	if !ok {
+		return fmt.Errorf("Failed to convert to *AzureClient, got: %T", meta)
+	}
+	managementClient := azureClient.managementClient
+	vmClient := virtualmachine.NewClient(managementClient)
+
+	name := d.Get("name").(string)
+	serviceName := d.Get("service_name").(string)
+
+	log.Println("[INFO] Fetching current role configuration for update.")
+	role, err := vmClient.GetRole(serviceName, name, name)
+	if err != nil {
+		return fmt.Errorf("Failed to get current role configuration: %s", err)
+	}
+
+	if csize {
+		size := d.Get("size").(string)
+		if !vmutils.IsRoleSizeValid(size) {
+			return fmt.Errorf("Invalid role size: %q", size)
+		}
+		role.RoleSize = size
+	}
+
+	if cdisks {
+		if err := updateInstanceDataDisks(d, role, name); err != nil {
+			return err
+		}
+	}
+
+	if cendpoints {
+		if err := updateInstanceEndpoints(d, role); err != nil {
+			return err
+		}
+	}
+
+	reqID, err := vmClient.UpdateRole(serviceName, name, name, *role)
+	if err != nil {
+		return fmt.Errorf("Failed to issue role update: %s", err)
+	}
+	if err := azureClient.waitForOperationWithRetry(reqID); err != nil {
+		return fmt.Errorf("Failed updating role: %s", err)
+	}
+
+	return resourceAzureInstanceRead(d, meta)
+}
+
+// applyDataDiskOverrides sets the 'caching'/'media_link'/'source_media_link'
+// fields of a 'data_disk' block onto the DataVirtualHardDisk entry
+// vmutils.AppendDataDisk just added for lun, since AppendDataDisk only
+// accounts for the lun/size and the disk's own generated VHD path.
+func applyDataDiskOverrides(role *virtualmachine.Role, lun int, disk map[string]interface{}) {
+	caching := disk["caching"].(string)
+	mediaLink := disk["media_link"].(string)
+	sourceMediaLink := disk["source_media_link"].(string)
+
+	for i := range role.DataVirtualHardDisks {
+		if role.DataVirtualHardDisks[i].Lun != lun {
+			continue
+		}
+		if caching != "" {
+			role.DataVirtualHardDisks[i].HostCaching = caching
+		}
+		if mediaLink != "" {
+			role.DataVirtualHardDisks[i].MediaLink = mediaLink
+		}
+		if sourceMediaLink != "" {
+			role.DataVirtualHardDisks[i].SourceMediaLink = sourceMediaLink
+		}
+		break
+	}
+}
+
+// updateInstanceDataDisks diffs the 'data_disk' blocks between the old and
+// new state and attaches/detaches the corresponding disks on role.
+func updateInstanceDataDisks(d *schema.ResourceData, role *virtualmachine.Role, name string) error {
+	old, new := d.GetChange("data_disk")
+	oldDisks := old.([]interface{})
+	newDisks := new.([]interface{})
+
+	oldLuns := map[int]bool{}
+	for _, v := range oldDisks {
+		oldLuns[v.(map[string]interface{})["lun"].(int)] = true
+	}
+	newLuns := map[int]bool{}
+	for _, v := range newDisks {
+		newLuns[v.(map[string]interface{})["lun"].(int)] = true
+	}
+
+	// remove disks which are no longer present:
+	for lun := range oldLuns {
+		if !newLuns[lun] {
+			log.Printf("[INFO] Removing data disk with lun %d.", lun)
+			if err := vmutils.RemoveDataDisk(role, lun); err != nil {
+				return fmt.Errorf("Failed to remove data disk with lun %d: %s", lun, err)
+			}
+		}
+	}
+
+	// attach newly added disks:
+	for _, v := range newDisks {
+		disk := v.(map[string]interface{})
+		lun := disk["lun"].(int)
+		if !oldLuns[lun] {
+			log.Printf("[INFO] Attaching data disk with lun %d.", lun)
+			label := azureid.NewDeterministicID("data-disk", fmt.Sprintf("%s-%d", name, lun))
+			if err := vmutils.AppendDataDisk(role, lun, disk["size_gb"].(int), label); err != nil {
+				return fmt.Errorf("Failed to attach data disk with lun %d: %s", lun, err)
+			}
+			applyDataDiskOverrides(role, lun, disk)
+		}
+	}
+
+	return nil
+}
+
+// updateInstanceEndpoints diffs the 'endpoint' blocks between the old and
+// new state and adds/removes/reconfigures the corresponding external ports
+// on role. Endpoints are compared by their full contents, not just by
+// name, so that in-place edits (e.g. a changed public_port or probe) are
+// re-applied instead of being silently dropped.
+func updateInstanceEndpoints(d *schema.ResourceData, role *virtualmachine.Role) error {
+	old, new := d.GetChange("endpoint")
+	oldEndpoints := old.([]interface{})
+	newEndpoints := new.([]interface{})
+
+	oldByName := map[string]map[string]interface{}{}
+	for _, v := range oldEndpoints {
+		endpoint := v.(map[string]interface{})
+		oldByName[endpoint["name"].(string)] = endpoint
+	}
+	newNames := map[string]bool{}
+	for _, v := range newEndpoints {
+		newNames[v.(map[string]interface{})["name"].(string)] = true
+	}
+
+	// remove endpoints which are no longer present:
+	for epName := range oldByName {
+		if !newNames[epName] {
+			log.Printf("[INFO] Removing endpoint %q.", epName)
+			if err := vmutils.RemoveExternalPort(role, epName); err != nil {
+				return fmt.Errorf("Failed to remove endpoint %q: %s", epName, err)
+			}
+		}
+	}
+
+	// add newly added endpoints, and reconfigure existing ones whose
+	// contents have changed:
+	for _, v := range newEndpoints {
+		endpoint := v.(map[string]interface{})
+		epName := endpoint["name"].(string)
+		oldEndpoint, existed := oldByName[epName]
+		if !existed {
+			log.Printf("[INFO] Adding endpoint %q.", epName)
+			if err := configureInstanceEndpoint(role, endpoint); err != nil {
+				return fmt.Errorf("Failed to add endpoint %q: %s", epName, err)
+			}
+		} else if !reflect.DeepEqual(oldEndpoint, endpoint) {
+			log.Printf("[INFO] Reconfiguring endpoint %q.", epName)
+			if err := vmutils.RemoveExternalPort(role, epName); err != nil {
+				return fmt.Errorf("Failed to remove endpoint %q for reconfiguration: %s", epName, err)
+			}
+			if err := configureInstanceEndpoint(role, endpoint); err != nil {
+				return fmt.Errorf("Failed to reconfigure endpoint %q: %s", epName, err)
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -5,11 +5,22 @@ import (
 	"log"
 
 	"github.com/Azure/azure-sdk-for-go/management/virtualnetwork"
+	"github.com/hashicorp/terraform/helper/azureid"
 	"github.com/hashicorp/terraform/helper/schema"
 )
 
 // resourceAzureLocalNetworkConnetion returns the schema.Resource associated to an
 // Azure hosted service.
+//
+// This resource still talks to the classic virtualnetwork.Client
+// unconditionally: it edits the single, account-wide network configuration
+// document also shared by resourceAzureVirtualNetwork/resourceAzureSubnet/
+// resourceAzureDnsServer, which has no ARM equivalent (ARM models local
+// network gateways as their own resource, not an entry in a shared
+// document). Only azure_security_group/azure_security_rule have an ARM
+// backend. Config.Client does not build the classic management client
+// when 'mode = "arm"', so this resource, like the other resources that
+// use azureClient.managementClient directly, has no client in that mode.
 func resourceAzureLocalNetworkConnection() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceAzureLocalNetworkConnectionCreate,
@@ -94,7 +105,7 @@ func resourceAzureLocalNetworkConnectionCreate(d *schema.ResourceData, meta inte
 	}
 
 	azureClient.mutex.Unlock()
-	d.SetId(getRandomStringLabel(50))
+	d.SetId(azureid.NewUniqueID("local-network"))
 	return nil
 }
 
@@ -0,0 +1,94 @@
+package azure
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAzureHostedServiceMigrateState(t *testing.T) {
+	cases := map[string]struct {
+		StateVersion int
+		Attributes   map[string]string
+		Expected     string
+	}{
+		"v0 label becomes the id": {
+			StateVersion: 0,
+			Attributes: map[string]string{
+				"label":        "hosted-service-deadbeef",
+				"service_name": "example",
+			},
+			Expected: "hosted-service-deadbeef",
+		},
+	}
+
+	for name, tc := range cases {
+		is := &terraform.InstanceState{
+			ID:         "",
+			Attributes: tc.Attributes,
+		}
+
+		is, err := resourceAzureHostedServiceMigrateState(tc.StateVersion, is, nil)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", name, err)
+		}
+
+		if is.ID != tc.Expected {
+			t.Fatalf("%s: expected ID %q, got %q", name, tc.Expected, is.ID)
+		}
+		if _, ok := is.Attributes["label"]; ok {
+			t.Fatalf("%s: expected 'label' attribute to be removed", name)
+		}
+	}
+}
+
+func TestAzureVirtualNetworkMigrateState(t *testing.T) {
+	cases := map[string]struct {
+		StateVersion int
+		Attributes   map[string]string
+		ExpectedID   string
+		Expected     map[string]string
+	}{
+		"v0 label, dns servers and flat subnet migrate to v1": {
+			StateVersion: 0,
+			Attributes: map[string]string{
+				"label":              "virtual-network-deadbeef",
+				"dns_servers.first":  "10.0.0.1",
+				"dns_servers.second": "10.0.0.2",
+				"subnet_name":        "subnet1",
+				"subnet_prefix":      "10.0.0.0/24",
+			},
+			ExpectedID: "virtual-network-deadbeef",
+			Expected: map[string]string{
+				"dns_servers_names.#": "2",
+				"dns_servers_names.0": "first",
+				"dns_servers_names.1": "second",
+				"subnet.#":            "1",
+				"subnet.0.name":       "subnet1",
+				"subnet.0.prefix":     "10.0.0.0/24",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		is := &terraform.InstanceState{
+			ID:         "",
+			Attributes: tc.Attributes,
+		}
+
+		is, err := resourceAzureVirtualNetworkMigrateState(tc.StateVersion, is, nil)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", name, err)
+		}
+
+		if is.ID != tc.ExpectedID {
+			t.Fatalf("%s: expected ID %q, got %q", name, tc.ExpectedID, is.ID)
+		}
+
+		for k, v := range tc.Expected {
+			if is.Attributes[k] != v {
+				t.Fatalf("%s: expected attribute %q to be %q, got %q", name, k, v, is.Attributes[k])
+			}
+		}
+	}
+}
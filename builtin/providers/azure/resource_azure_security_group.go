@@ -5,7 +5,7 @@ import (
 	"log"
 
 	"github.com/Azure/azure-sdk-for-go/management"
-	netsecgroup "github.com/Azure/azure-sdk-for-go/management/networksecuritygroup"
+	"github.com/hashicorp/terraform/helper/azureid"
 	"github.com/hashicorp/terraform/helper/schema"
 )
 
@@ -15,7 +15,7 @@ func resourceAzureSecurityGroup() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceAzureSecurityGroupCreate,
 		Read:   resourceAzureSecurityGroupRead,
-		//	Update: resourceAzureSecurityGroupUpdate,
+		Update: resourceAzureSecurityGroupUpdate,
 		Exists: resourceAzureSecurityGroupExists,
 		Delete: resourceAzureSecurityGroupDelete,
 
@@ -34,6 +34,13 @@ func resourceAzureSecurityGroup() *schema.Resource {
 				ForceNew:    true,
 				Description: parameterDescriptions["location"],
 			},
+			"security_rule": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: resourceAzureSecurityRuleSchema(true),
+				},
+			},
 		},
 	}
 }
@@ -45,44 +52,113 @@ func resourceAzureSecurityGroupCreate(d *schema.ResourceData, meta interface{})
 	if !ok {
 		return fmt.Errorf("Failed to convert to *AzureClient, got: %T", meta)
 	}
-	managementClient := azureClient.managementClient
-	netSecClient := netsecgroup.NewClient(managementClient)
 
 	name := d.Get("name").(string)
 	location := d.Get("location").(string)
-	label := getRandomStringLabel(50)
+	label := azureid.NewUniqueID("security-group")
 
 	// create the network security group:
 	log.Println("[INFO] Sending network security group creating request to Azure.")
-	reqID, err := netSecClient.CreateNetworkSecurityGroup(
-		name,
-		label,
-		location,
-	)
-	if err != nil {
-		return fmt.Errorf("Error whilst sending network security group create request to Azure: %s", err)
-	}
-
-	err = managementClient.WaitAsyncOperation(reqID)
-	if err != nil {
+	if err := azureClient.networkSecurityBackend.CreateSecurityGroup(name, label, location); err != nil {
 		return fmt.Errorf("Error creating network security group on Azure: %s", err)
 	}
 	d.SetId(label)
+
+	if nrules := d.Get("security_rule.#").(int); nrules > 0 {
+		for i := 0; i < nrules; i++ {
+			rule := azureSecurityRuleFromMap(d.Get(fmt.Sprintf("security_rule.%d", i)).(map[string]interface{}))
+			log.Println("[INFO] Sending inline network security rule set request to Azure.")
+			if err := azureClient.networkSecurityBackend.SetSecurityRule(name, rule); err != nil {
+				return fmt.Errorf("Error whilst setting inline network security rule %q: %s", rule.Name, err)
+			}
+		}
+	}
+
 	return nil
 }
 
 // resourceAzureSecurityGroupRead does all the necessary API calls to
 // read the state of the network security group off Azure.
 func resourceAzureSecurityGroupRead(d *schema.ResourceData, meta interface{}) error {
-	_, err := resourceAzureSecurityGroupExists(d, meta)
-	return err
+	exists, err := resourceAzureSecurityGroupExists(d, meta)
+	if err != nil || !exists {
+		return err
+	}
+
+	azureClient, ok := meta.(*AzureClient)
+	if !ok {
+		return fmt.Errorf("Failed to convert to *AzureClient, got: %T", meta)
+	}
+
+	name := d.Get("name").(string)
+	secGroup, err := azureClient.networkSecurityBackend.GetSecurityGroup(name)
+	if err != nil {
+		return fmt.Errorf("Error querying Azure for network security group %q: %s", name, err)
+	}
+
+	rules := make([]map[string]interface{}, 0, len(secGroup.Rules))
+	for _, rule := range secGroup.Rules {
+		rules = append(rules, map[string]interface{}{
+			"name":                       rule.Name,
+			"type":                       rule.Type,
+			"priority":                   rule.Priority,
+			"action":                     rule.Action,
+			"source_address_prefix":      rule.SourceAddressPrefix,
+			"source_port_range":          rule.SourcePortRange,
+			"destination_address_prefix": rule.DestinationAddressPrefix,
+			"destination_port_range":     rule.DestinationPortRange,
+			"protocol":                   rule.Protocol,
+		})
+	}
+	d.Set("security_rule", rules)
+
+	return nil
 }
 
 // resourceAzureSecurityGroupUpdate does all the necessary API calls to
-// update the state of the network security group on Azure.
-// func resourceAzureSecurityGroupUpdate(d *schema.ResourceData, meta interface{}) error {
-// redundant as all the parameters force new creation on change.
-// }
+// reconcile the 'security_rule' blocks declared in configuration against
+// the live rules on the network security group, without forcing the
+// group itself to be recreated.
+func resourceAzureSecurityGroupUpdate(d *schema.ResourceData, meta interface{}) error {
+	azureClient, ok := meta.(*AzureClient)
+	if !ok {
+		return fmt.Errorf("Failed to convert to *AzureClient, got: %T", meta)
+	}
+	if !d.HasChange("security_rule") {
+		return nil
+	}
+
+	name := d.Get("name").(string)
+	old, new := d.GetChange("security_rule")
+	oldRules := old.([]interface{})
+	newRules := new.([]interface{})
+
+	newByName := map[string]bool{}
+	for _, r := range newRules {
+		newByName[r.(map[string]interface{})["name"].(string)] = true
+	}
+
+	for _, r := range oldRules {
+		ruleName := r.(map[string]interface{})["name"].(string)
+		if newByName[ruleName] {
+			continue
+		}
+		log.Printf("[INFO] Removing stale inline network security rule %q.", ruleName)
+		if err := azureClient.networkSecurityBackend.DeleteSecurityRule(name, ruleName); err != nil {
+			return fmt.Errorf("Error whilst deleting inline network security rule %q: %s", ruleName, err)
+		}
+	}
+
+	for _, r := range newRules {
+		rule := azureSecurityRuleFromMap(r.(map[string]interface{}))
+		log.Printf("[INFO] Setting inline network security rule %q.", rule.Name)
+		if err := azureClient.networkSecurityBackend.SetSecurityRule(name, rule); err != nil {
+			return fmt.Errorf("Error whilst setting inline network security rule %q: %s", rule.Name, err)
+		}
+	}
+
+	return resourceAzureSecurityGroupRead(d, meta)
+}
 
 // resourceAzureSecurityGroupExists does all the necessary API calls to
 // check if the network security group already exists on Azure.
@@ -91,11 +167,10 @@ func resourceAzureSecurityGroupExists(d *schema.ResourceData, meta interface{})
 	if !ok {
 		return false, fmt.Errorf("Failed to convert to *AzureClient, got: %T", meta)
 	}
-	netSecClient := netsecgroup.NewClient(azureClient.managementClient)
 
 	name := d.Get("name").(string)
 	log.Println("[INFO] Sending network security group query to Azure.")
-	_, err := netSecClient.GetNetworkSecurityGroup(name)
+	_, err := azureClient.networkSecurityBackend.GetSecurityGroup(name)
 	if err != nil {
 		if !management.IsResourceNotFoundError(err) {
 			return false, fmt.Errorf("Error querying Azure for network security group: %s", err)
@@ -117,17 +192,10 @@ func resourceAzureSecurityGroupDelete(d *schema.ResourceData, meta interface{})
 	if !ok {
 		return fmt.Errorf("Failed to convert to *AzureClient, got: %T", meta)
 	}
-	managementClient := azureClient.managementClient
-	netSecClient := netsecgroup.NewClient(managementClient)
 
 	name := d.Get("name").(string)
 	log.Println("[INFO] Issuing network security delete to Azure.")
-	reqID, err := netSecClient.DeleteNetworkSecurityGroup(name)
-	if err != nil {
-		return fmt.Errorf("Error whilst issuing Azure network security group deletion: %s", err)
-	}
-	err = managementClient.WaitAsyncOperation(reqID)
-	if err != nil {
+	if err := azureClient.networkSecurityBackend.DeleteSecurityGroup(name); err != nil {
 		return fmt.Errorf("Error in Azure network security group deletion: %s", err)
 	}
 
@@ -0,0 +1,247 @@
+package azure
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/Azure/azure-sdk-for-go/management"
+	netsecgroup "github.com/Azure/azure-sdk-for-go/management/networksecuritygroup"
+	"github.com/hashicorp/terraform/helper/azureid"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// resourceAzureSecurityGroupAssociation returns the *schema.Resource
+// associated to the binding of a network security group to either a
+// virtual network subnet or a role's network interface, managed
+// independently of the NSG, subnet, and instance resources themselves.
+func resourceAzureSecurityGroupAssociation() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAzureSecurityGroupAssociationCreate,
+		Read:   resourceAzureSecurityGroupAssociationRead,
+		Exists: resourceAzureSecurityGroupAssociationExists,
+		Delete: resourceAzureSecurityGroupAssociationDelete,
+
+		SchemaVersion: 1,
+
+		Schema: map[string]*schema.Schema{
+			"security_group": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"virtual_network": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"subnet": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"virtual_machine": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"network_interface": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+		},
+	}
+}
+
+// azureSecurityGroupAssociationTarget describes which of the two bindable
+// targets (subnet or role network interface) a resourceAzureSecurityGroup
+// Association refers to.
+type azureSecurityGroupAssociationTarget struct {
+	VirtualNetwork   string
+	Subnet           string
+	VirtualMachine   string
+	NetworkInterface string
+}
+
+// resourceAzureSecurityGroupAssociationTarget validates and extracts the
+// configured target out of d, requiring exactly one of the two supported
+// {virtual_network, subnet} / {virtual_machine, network_interface} pairs.
+func resourceAzureSecurityGroupAssociationTarget(d *schema.ResourceData) (azureSecurityGroupAssociationTarget, error) {
+	vnet := d.Get("virtual_network").(string)
+	subnet := d.Get("subnet").(string)
+	vm := d.Get("virtual_machine").(string)
+	nic := d.Get("network_interface").(string)
+
+	isSubnet := vnet != "" || subnet != ""
+	isNIC := vm != "" || nic != ""
+
+	switch {
+	case isSubnet && isNIC:
+		return azureSecurityGroupAssociationTarget{}, fmt.Errorf(
+			"Only one of {virtual_network, subnet} or {virtual_machine, network_interface} may be set")
+	case isSubnet:
+		if vnet == "" || subnet == "" {
+			return azureSecurityGroupAssociationTarget{}, fmt.Errorf("Both 'virtual_network' and 'subnet' must be set")
+		}
+		return azureSecurityGroupAssociationTarget{VirtualNetwork: vnet, Subnet: subnet}, nil
+	case isNIC:
+		if vm == "" || nic == "" {
+			return azureSecurityGroupAssociationTarget{}, fmt.Errorf("Both 'virtual_machine' and 'network_interface' must be set")
+		}
+		return azureSecurityGroupAssociationTarget{VirtualMachine: vm, NetworkInterface: nic}, nil
+	default:
+		return azureSecurityGroupAssociationTarget{}, fmt.Errorf(
+			"One of {virtual_network, subnet} or {virtual_machine, network_interface} must be set")
+	}
+}
+
+// resourceAzureSecurityGroupAssociationCreate does all the necessary API
+// calls to attach the network security group to the configured target.
+func resourceAzureSecurityGroupAssociationCreate(d *schema.ResourceData, meta interface{}) error {
+	azureClient, ok := meta.(*AzureClient)
+	if !ok {
+		return fmt.Errorf("Failed to convert to *AzureClient, got: %T", meta)
+	}
+	netSecClient := netsecgroup.NewClient(azureClient.managementClient)
+
+	target, err := resourceAzureSecurityGroupAssociationTarget(d)
+	if err != nil {
+		return err
+	}
+	secGroupName := d.Get("security_group").(string)
+
+	var reqID management.OperationID
+	if target.Subnet != "" {
+		log.Printf("[INFO] Attaching network security group %q to subnet %q.", secGroupName, target.Subnet)
+		reqID, err = netSecClient.AddNetworkSecurityToSubnet(secGroupName, target.Subnet, target.VirtualNetwork)
+	} else {
+		log.Printf("[INFO] Attaching network security group %q to network interface %q.", secGroupName, target.NetworkInterface)
+		reqID, err = netSecClient.AddNetworkSecurityToRole(secGroupName, target.VirtualMachine, target.NetworkInterface)
+	}
+	if err != nil {
+		return fmt.Errorf("Error whilst attaching network security group %q: %s", secGroupName, err)
+	}
+	if err := azureClient.waitForOperationWithRetry(reqID); err != nil {
+		return fmt.Errorf("Error attaching network security group %q: %s", secGroupName, err)
+	}
+
+	d.SetId(azureid.NewDeterministicID("security-group-association", secGroupName+target.VirtualNetwork+target.Subnet+target.VirtualMachine+target.NetworkInterface))
+	return resourceAzureSecurityGroupAssociationRead(d, meta)
+}
+
+// resourceAzureSecurityGroupAssociationRead does all the necessary API
+// calls to reconcile the association's state against what's live on
+// Azure, to catch drift from the NSG being detached out-of-band.
+func resourceAzureSecurityGroupAssociationRead(d *schema.ResourceData, meta interface{}) error {
+	azureClient, ok := meta.(*AzureClient)
+	if !ok {
+		return fmt.Errorf("Failed to convert to *AzureClient, got: %T", meta)
+	}
+	netSecClient := netsecgroup.NewClient(azureClient.managementClient)
+
+	target, err := resourceAzureSecurityGroupAssociationTarget(d)
+	if err != nil {
+		return err
+	}
+	secGroupName := d.Get("security_group").(string)
+
+	var current string
+	if target.Subnet != "" {
+		secGroup, err := netSecClient.GetNetworkSecurityGroupForSubnet(target.Subnet, target.VirtualNetwork)
+		if err != nil {
+			if !management.IsResourceNotFoundError(err) {
+				return fmt.Errorf("Error reading network security group for subnet %q: %s", target.Subnet, err)
+			}
+		} else {
+			current = secGroup.Name
+		}
+	} else {
+		secGroup, err := netSecClient.GetNetworkSecurityGroupForRole(target.VirtualMachine, target.NetworkInterface)
+		if err != nil {
+			if !management.IsResourceNotFoundError(err) {
+				return fmt.Errorf("Error reading network security group for network interface %q: %s", target.NetworkInterface, err)
+			}
+		} else {
+			current = secGroup.Name
+		}
+	}
+
+	if current != secGroupName {
+		// the association no longer holds on Azure; drop it from state.
+		d.SetId("")
+	}
+
+	return nil
+}
+
+// resourceAzureSecurityGroupAssociationExists does all the necessary API
+// calls to check whether the association still holds on Azure.
+func resourceAzureSecurityGroupAssociationExists(d *schema.ResourceData, meta interface{}) (bool, error) {
+	azureClient, ok := meta.(*AzureClient)
+	if !ok {
+		return false, fmt.Errorf("Failed to convert to *AzureClient, got: %T", meta)
+	}
+	netSecClient := netsecgroup.NewClient(azureClient.managementClient)
+
+	target, err := resourceAzureSecurityGroupAssociationTarget(d)
+	if err != nil {
+		return false, err
+	}
+	secGroupName := d.Get("security_group").(string)
+
+	var secGroup netsecgroup.NetworkSecurityGroup
+	if target.Subnet != "" {
+		secGroup, err = netSecClient.GetNetworkSecurityGroupForSubnet(target.Subnet, target.VirtualNetwork)
+	} else {
+		secGroup, err = netSecClient.GetNetworkSecurityGroupForRole(target.VirtualMachine, target.NetworkInterface)
+	}
+	if err != nil {
+		if management.IsResourceNotFoundError(err) {
+			d.SetId("")
+			return false, nil
+		}
+		return false, fmt.Errorf("Error querying Azure for network security group association: %s", err)
+	}
+
+	if secGroup.Name != secGroupName {
+		d.SetId("")
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// resourceAzureSecurityGroupAssociationDelete does all the necessary API
+// calls to detach the network security group from its target, without
+// deleting the network security group itself.
+func resourceAzureSecurityGroupAssociationDelete(d *schema.ResourceData, meta interface{}) error {
+	azureClient, ok := meta.(*AzureClient)
+	if !ok {
+		return fmt.Errorf("Failed to convert to *AzureClient, got: %T", meta)
+	}
+	netSecClient := netsecgroup.NewClient(azureClient.managementClient)
+
+	target, err := resourceAzureSecurityGroupAssociationTarget(d)
+	if err != nil {
+		return err
+	}
+	secGroupName := d.Get("security_group").(string)
+
+	var reqID management.OperationID
+	if target.Subnet != "" {
+		log.Printf("[INFO] Detaching network security group %q from subnet %q.", secGroupName, target.Subnet)
+		reqID, err = netSecClient.RemoveNetworkSecurityGroupFromSubnet(secGroupName, target.Subnet, target.VirtualNetwork)
+	} else {
+		log.Printf("[INFO] Detaching network security group %q from network interface %q.", secGroupName, target.NetworkInterface)
+		reqID, err = netSecClient.RemoveNetworkSecurityGroupFromRole(secGroupName, target.VirtualMachine, target.NetworkInterface)
+	}
+	if err != nil {
+		return fmt.Errorf("Error whilst detaching network security group %q: %s", secGroupName, err)
+	}
+	if err := azureClient.waitForOperationWithRetry(reqID); err != nil {
+		return fmt.Errorf("Error detaching network security group %q: %s", secGroupName, err)
+	}
+
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,218 @@
+package azure
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/Azure/azure-sdk-for-go/management"
+	netsecgroup "github.com/Azure/azure-sdk-for-go/management/networksecuritygroup"
+	"github.com/hashicorp/terraform/helper/azureid"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// resourceAzureSecurityRule returns the *schema.Resource associated to a
+// single rule of an Azure network security group, managed independently of
+// the rest of that group's configuration.
+func resourceAzureSecurityRule() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAzureSecurityRuleCreate,
+		Read:   resourceAzureSecurityRuleRead,
+		Update: resourceAzureSecurityRuleCreate,
+		Exists: resourceAzureSecurityRuleExists,
+		Delete: resourceAzureSecurityRuleDelete,
+
+		SchemaVersion: 1,
+
+		Schema: resourceAzureSecurityRuleSchema(false),
+	}
+}
+
+// resourceAzureSecurityRuleSchema returns the set of fields shared by the
+// standalone azure_security_rule resource and the inline 'security_rule'
+// block on resourceAzureSecurityGroup. forInline trims the fields that
+// the inline block inherits from its parent (security_group_name).
+func resourceAzureSecurityRuleSchema(forInline bool) map[string]*schema.Schema {
+	s := map[string]*schema.Schema{
+		"name": &schema.Schema{
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: parameterDescriptions["name"],
+		},
+		"type": &schema.Schema{
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"priority": &schema.Schema{
+			Type:     schema.TypeInt,
+			Required: true,
+		},
+		"action": &schema.Schema{
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"source_address_prefix": &schema.Schema{
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"source_port_range": &schema.Schema{
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"destination_address_prefix": &schema.Schema{
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"destination_port_range": &schema.Schema{
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"protocol": &schema.Schema{
+			Type:     schema.TypeString,
+			Required: true,
+		},
+	}
+
+	if !forInline {
+		s["security_group_name"] = &schema.Schema{
+			Type:     schema.TypeString,
+			Required: true,
+			ForceNew: true,
+		}
+	}
+
+	return s
+}
+
+// azureSecurityRuleFromMap builds a netsecgroup.RuleSetParameters out of the
+// fields of a 'security_rule' map, as read off either a standalone
+// azure_security_rule resource or an inline block on azure_security_group.
+func azureSecurityRuleFromMap(rule map[string]interface{}) netsecgroup.RuleSetParameters {
+	return netsecgroup.RuleSetParameters{
+		Name:                     rule["name"].(string),
+		Type:                     rule["type"].(string),
+		Priority:                 rule["priority"].(int),
+		Action:                   rule["action"].(string),
+		SourceAddressPrefix:      rule["source_address_prefix"].(string),
+		SourcePortRange:          rule["source_port_range"].(string),
+		DestinationAddressPrefix: rule["destination_address_prefix"].(string),
+		DestinationPortRange:     rule["destination_port_range"].(string),
+		Protocol:                 rule["protocol"].(string),
+	}
+}
+
+// resourceAzureSecurityRuleCreate does all the necessary API calls to
+// create (or, since Azure's API for rules is a declarative "set" call,
+// update) a network security rule on Azure.
+func resourceAzureSecurityRuleCreate(d *schema.ResourceData, meta interface{}) error {
+	azureClient, ok := meta.(*AzureClient)
+	if !ok {
+		return fmt.Errorf("Failed to convert to *AzureClient, got: %T", meta)
+	}
+	secGroupName := d.Get("security_group_name").(string)
+	name := d.Get("name").(string)
+	rule := azureSecurityRuleFromMap(map[string]interface{}{
+		"name":                       name,
+		"type":                       d.Get("type").(string),
+		"priority":                   d.Get("priority").(int),
+		"action":                     d.Get("action").(string),
+		"source_address_prefix":      d.Get("source_address_prefix").(string),
+		"source_port_range":          d.Get("source_port_range").(string),
+		"destination_address_prefix": d.Get("destination_address_prefix").(string),
+		"destination_port_range":     d.Get("destination_port_range").(string),
+		"protocol":                   d.Get("protocol").(string),
+	})
+
+	log.Println("[INFO] Sending network security rule set request to Azure.")
+	if err := azureClient.networkSecurityBackend.SetSecurityRule(secGroupName, rule); err != nil {
+		return fmt.Errorf("Error whilst setting network security rule %q on %q: %s", name, secGroupName, err)
+	}
+
+	d.SetId(azureid.NewDeterministicID("security-rule", secGroupName+name))
+	return resourceAzureSecurityRuleRead(d, meta)
+}
+
+// resourceAzureSecurityRuleRead does all the necessary API calls to read
+// the state of the network security rule off Azure.
+func resourceAzureSecurityRuleRead(d *schema.ResourceData, meta interface{}) error {
+	azureClient, ok := meta.(*AzureClient)
+	if !ok {
+		return fmt.Errorf("Failed to convert to *AzureClient, got: %T", meta)
+	}
+	secGroupName := d.Get("security_group_name").(string)
+	name := d.Get("name").(string)
+
+	secGroup, err := azureClient.networkSecurityBackend.GetSecurityGroup(secGroupName)
+	if err != nil {
+		if management.IsResourceNotFoundError(err) {
+			d.SetId("")
+			return nil
+		}
+		return fmt.Errorf("Error querying Azure for network security group %q: %s", secGroupName, err)
+	}
+
+	for _, rule := range secGroup.Rules {
+		if rule.Name != name {
+			continue
+		}
+		d.Set("type", rule.Type)
+		d.Set("priority", rule.Priority)
+		d.Set("action", rule.Action)
+		d.Set("source_address_prefix", rule.SourceAddressPrefix)
+		d.Set("source_port_range", rule.SourcePortRange)
+		d.Set("destination_address_prefix", rule.DestinationAddressPrefix)
+		d.Set("destination_port_range", rule.DestinationPortRange)
+		d.Set("protocol", rule.Protocol)
+		return nil
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceAzureSecurityRuleExists does all the necessary API calls to
+// check if the network security rule still exists on Azure.
+func resourceAzureSecurityRuleExists(d *schema.ResourceData, meta interface{}) (bool, error) {
+	azureClient, ok := meta.(*AzureClient)
+	if !ok {
+		return false, fmt.Errorf("Failed to convert to *AzureClient, got: %T", meta)
+	}
+	secGroupName := d.Get("security_group_name").(string)
+	name := d.Get("name").(string)
+
+	secGroup, err := azureClient.networkSecurityBackend.GetSecurityGroup(secGroupName)
+	if err != nil {
+		if management.IsResourceNotFoundError(err) {
+			d.SetId("")
+			return false, nil
+		}
+		return false, fmt.Errorf("Error querying Azure for network security group %q: %s", secGroupName, err)
+	}
+
+	for _, rule := range secGroup.Rules {
+		if rule.Name == name {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// resourceAzureSecurityRuleDelete does all the necessary API calls to
+// delete a network security rule off Azure.
+func resourceAzureSecurityRuleDelete(d *schema.ResourceData, meta interface{}) error {
+	azureClient, ok := meta.(*AzureClient)
+	if !ok {
+		return fmt.Errorf("Failed to convert to *AzureClient, got: %T", meta)
+	}
+	secGroupName := d.Get("security_group_name").(string)
+	name := d.Get("name").(string)
+
+	log.Println("[INFO] Issuing network security rule delete to Azure.")
+	if err := azureClient.networkSecurityBackend.DeleteSecurityRule(secGroupName, name); err != nil {
+		return fmt.Errorf("Error in Azure network security rule deletion: %s", err)
+	}
+
+	d.SetId("")
+	return nil
+}
@@ -3,9 +3,11 @@ package azure
 import (
 	"fmt"
 	"log"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/management"
 	"github.com/Azure/azure-sdk-for-go/management/storageservice"
+	"github.com/hashicorp/terraform/helper/azureid"
 	"github.com/hashicorp/terraform/helper/schema"
 )
 
@@ -15,7 +17,7 @@ func resourceAzureStorageService() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceAzureStorageServiceCreate,
 		Read:   resourceAzureStorageServiceRead,
-		// Update: resourceAzureStorageServiceUpdate,
+		Update: resourceAzureStorageServiceUpdate,
 		Exists: resourceAzureStorageServiceExists,
 		Delete: resourceAzureStorageServiceDelete,
 
@@ -41,8 +43,9 @@ func resourceAzureStorageService() *schema.Resource {
 				Description: parameterDescriptions["account_type"],
 			},
 			"url": &schema.Schema{
-				Type:     schema.TypeString,
+				Type:     schema.TypeMap,
 				Computed: true,
+				Elem:     schema.TypeString,
 			},
 			"description": &schema.Schema{
 				Type:        schema.TypeString,
@@ -82,7 +85,7 @@ func resourceAzureStorageServiceCreate(d *schema.ResourceData, meta interface{})
 	accountType := storageservice.AccountType(d.Get("account_type").(string))
 	affinityGroup := d.Get("affinity_group").(string)
 	description := d.Get("description").(string)
-	label := getRandomStringLabel(20)
+	label := azureid.NewUniqueID("storage-service")
 	var props []storageservice.ExtendedProperty
 	if given := d.Get("properties").(map[string]interface{}); len(given) > 0 {
 		props = []storageservice.ExtendedProperty{}
@@ -116,10 +119,6 @@ func resourceAzureStorageServiceCreate(d *schema.ResourceData, meta interface{})
 		return fmt.Errorf("Failed updating the network configuration: %s", err)
 	}
 
-	// TODO(aznashwan): find work around here:
-	// get computed values:
-	// d.Set("url", svc.Url)
-
 	d.SetId(label)
 	return resourceAzureStorageServiceRead(d, meta)
 }
@@ -151,17 +150,70 @@ func resourceAzureStorageServiceRead(d *schema.ResourceData, meta interface{}) e
 	}
 
 	// read values:
-	d.Set("url", storsvc.URL)
+	urls := map[string]string{}
+	for _, endpoint := range storsvc.StorageServiceProperties.Endpoints {
+		switch {
+		case strings.Contains(endpoint, ".blob."):
+			urls["blob"] = endpoint
+		case strings.Contains(endpoint, ".queue."):
+			urls["queue"] = endpoint
+		case strings.Contains(endpoint, ".table."):
+			urls["table"] = endpoint
+		}
+	}
+	d.Set("url", urls)
 
 	return nil
 }
 
-// TODO(aznashwan): is this necessary?
 // resourceAzureStorageServiceUpdate does all the necessary API calls to
 // update the parameters of the storage service on Azure.
-// func resourceAzureStorageServiceUpdate(d *schema.ResourceData, meta interface{}) error {
-//	return nil
-// }
+func resourceAzureStorageServiceUpdate(d *schema.ResourceData, meta interface{}) error {
+	azureClient, ok := meta.(*AzureClient)
+	if !ok {
+		return fmt.Errorf("Failed to convert to *AzureClient, got: %T", meta)
+	}
+	storageServiceClient := storageservice.NewClient(azureClient.managementClient)
+
+	if !d.HasChange("account_type") && !d.HasChange("description") && !d.HasChange("properties") {
+		return nil
+	}
+
+	name := d.Get("name").(string)
+	accountType := storageservice.AccountType(d.Get("account_type").(string))
+	description := d.Get("description").(string)
+	var props []storageservice.ExtendedProperty
+	if given := d.Get("properties").(map[string]interface{}); len(given) > 0 {
+		props = []storageservice.ExtendedProperty{}
+		for k, v := range given {
+			props = append(props, storageservice.ExtendedProperty{
+				Name:  k,
+				Value: v.(string),
+			})
+		}
+	}
+
+	log.Println("[INFO] Sending storage service update request to Azure.")
+	reqID, err := storageServiceClient.UpdateStorageService(
+		name,
+		storageservice.StorageAccountUpdateParameters{
+			Description: description,
+			AccountType: accountType,
+			ExtendedProperties: storageservice.ExtendedPropertyList{
+				ExtendedProperty: props,
+			},
+		})
+	if err != nil {
+		return fmt.Errorf("Failed to update Azure storage service: %s", err)
+	}
+
+	log.Println("[DEBUG] Awaiting confirmation on storage service update.")
+	if err := azureClient.waitForOperationWithRetry(reqID); err != nil {
+		return fmt.Errorf("Error whilst updating storage service on Azure: %s", err)
+	}
+
+	return resourceAzureStorageServiceRead(d, meta)
+}
 
 // resourceAzureStorageServiceExists does all the necessary API calls to
 // check if the storage service exists on Azure.
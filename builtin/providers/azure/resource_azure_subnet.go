@@ -0,0 +1,340 @@
+package azure
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/Azure/azure-sdk-for-go/management"
+	netsecgroup "github.com/Azure/azure-sdk-for-go/management/networksecuritygroup"
+	"github.com/Azure/azure-sdk-for-go/management/virtualnetwork"
+	"github.com/hashicorp/terraform/helper/azureid"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// resourceAzureSubnet returns the *schema.Resource associated to a
+// single subnet of an Azure virtual network, managed independently of
+// the rest of that network's configuration.
+func resourceAzureSubnet() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAzureSubnetCreate,
+		Read:   resourceAzureSubnetRead,
+		Update: resourceAzureSubnetUpdate,
+		Exists: resourceAzureSubnetExists,
+		Delete: resourceAzureSubnetDelete,
+
+		SchemaVersion: 1,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: parameterDescriptions["name"],
+			},
+			"virtual_network_name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"address_prefix": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"security_group_name": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "",
+			},
+		},
+	}
+}
+
+// resourceAzureSubnetCreate does all the necessary API calls to add a new
+// subnet to the given virtual network's configuration.
+func resourceAzureSubnetCreate(d *schema.ResourceData, meta interface{}) error {
+	azureClient, ok := meta.(*AzureClient)
+	if !ok {
+		return fmt.Errorf("Failed to convert to *AzureClient, got: %T", meta)
+	}
+	managementClient := azureClient.managementClient
+	networkClient := virtualnetwork.NewClient(managementClient)
+	netSecClient := netsecgroup.NewClient(managementClient)
+
+	name := d.Get("name").(string)
+	vnetName := d.Get("virtual_network_name").(string)
+	prefix := d.Get("address_prefix").(string)
+	secGroupName := d.Get("security_group_name").(string)
+
+	log.Println("[INFO] Retrieving current network configuration from Azure.")
+	azureClient.mutex.Lock()
+	netConf, err := networkClient.GetVirtualNetworkConfiguration()
+	if err != nil {
+		azureClient.mutex.Unlock()
+		return fmt.Errorf("Error while retrieving current network configuration: %s", err)
+	}
+
+	vnets := netConf.Configuration.VirtualNetworkSites
+	var found bool
+	for i, vnet := range vnets {
+		if vnet.Name == vnetName {
+			found = true
+			vnets[i].Subnets = append(vnets[i].Subnets, virtualnetwork.Subnet{
+				Name:          name,
+				AddressPrefix: prefix,
+			})
+		}
+	}
+	if !found {
+		azureClient.mutex.Unlock()
+		return fmt.Errorf("Could not find virtual network %q to add subnet %q to", vnetName, name)
+	}
+
+	log.Println("[INFO] Sending updated network configuration back to Azure.")
+	reqID, err := networkClient.SetVirtualNetworkConfiguration(netConf)
+	if err != nil {
+		azureClient.mutex.Unlock()
+		return fmt.Errorf("Failed updating network configuration: %s", err)
+	}
+	err = managementClient.WaitForOperation(reqID, nil)
+	azureClient.mutex.Unlock()
+	if err != nil {
+		return fmt.Errorf("Failed updating the network configuration: %s", err)
+	}
+
+	if secGroupName != "" {
+		log.Println("[INFO] Attaching network security group to newly-created subnet.")
+		reqID, err := netSecClient.AddNetworkSecurityToSubnet(secGroupName, name, vnetName)
+		if err != nil {
+			return fmt.Errorf("Error issuing network security group attachment to subnet %q: %s", name, err)
+		}
+		if err := managementClient.WaitForOperation(reqID, nil); err != nil {
+			return fmt.Errorf("Error attaching network security group to subnet %q: %s", name, err)
+		}
+	}
+
+	d.SetId(azureid.NewDeterministicID("subnet", vnetName+name))
+	return resourceAzureSubnetRead(d, meta)
+}
+
+// resourceAzureSubnetRead does all the necessary API calls to read the
+// state of the subnet off Azure.
+func resourceAzureSubnetRead(d *schema.ResourceData, meta interface{}) error {
+	azureClient, ok := meta.(*AzureClient)
+	if !ok {
+		return fmt.Errorf("Failed to convert to *AzureClient, got: %T", meta)
+	}
+	managementClient := azureClient.managementClient
+	networkClient := virtualnetwork.NewClient(managementClient)
+	netSecClient := netsecgroup.NewClient(managementClient)
+
+	name := d.Get("name").(string)
+	vnetName := d.Get("virtual_network_name").(string)
+
+	log.Println("[INFO] Retrieving current network configuration from Azure.")
+	netConf, err := networkClient.GetVirtualNetworkConfiguration()
+	if err != nil {
+		return fmt.Errorf("Error while retrieving current network configuration: %s", err)
+	}
+
+	var found bool
+	for _, vnet := range netConf.Configuration.VirtualNetworkSites {
+		if vnet.Name != vnetName {
+			continue
+		}
+		for _, sub := range vnet.Subnets {
+			if sub.Name == name {
+				found = true
+				d.Set("address_prefix", sub.AddressPrefix)
+			}
+		}
+	}
+
+	if !found {
+		d.SetId("")
+		return nil
+	}
+
+	secGroup, err := netSecClient.GetNetworkSecurityGroupForSubnet(name, vnetName)
+	if err != nil {
+		if !management.IsResourceNotFoundError(err) {
+			return fmt.Errorf("Error reading network security group for subnet %q: %s", name, err)
+		}
+		d.Set("security_group_name", "")
+	} else {
+		d.Set("security_group_name", secGroup.Name)
+	}
+
+	return nil
+}
+
+// resourceAzureSubnetUpdate does all the necessary API calls to update the
+// subnet's configuration on Azure.
+func resourceAzureSubnetUpdate(d *schema.ResourceData, meta interface{}) error {
+	azureClient, ok := meta.(*AzureClient)
+	if !ok {
+		return fmt.Errorf("Failed to convert to *AzureClient, got: %T", meta)
+	}
+	managementClient := azureClient.managementClient
+	networkClient := virtualnetwork.NewClient(managementClient)
+	netSecClient := netsecgroup.NewClient(managementClient)
+
+	name := d.Get("name").(string)
+	vnetName := d.Get("virtual_network_name").(string)
+	cprefix := d.HasChange("address_prefix")
+
+	if cprefix {
+		log.Println("[INFO] Retrieving current network configuration from Azure.")
+		azureClient.mutex.Lock()
+		netConf, err := networkClient.GetVirtualNetworkConfiguration()
+		if err != nil {
+			azureClient.mutex.Unlock()
+			return fmt.Errorf("Error while retrieving current network configuration: %s", err)
+		}
+
+		prefix := d.Get("address_prefix").(string)
+		for i, vnet := range netConf.Configuration.VirtualNetworkSites {
+			if vnet.Name != vnetName {
+				continue
+			}
+			for j, sub := range vnet.Subnets {
+				if sub.Name == name {
+					netConf.Configuration.VirtualNetworkSites[i].Subnets[j].AddressPrefix = prefix
+				}
+			}
+		}
+
+		log.Println("[INFO] Sending updated network configuration back to Azure.")
+		reqID, err := networkClient.SetVirtualNetworkConfiguration(netConf)
+		if err != nil {
+			azureClient.mutex.Unlock()
+			return fmt.Errorf("Failed updating network configuration: %s", err)
+		}
+		err = managementClient.WaitForOperation(reqID, nil)
+		azureClient.mutex.Unlock()
+		if err != nil {
+			return fmt.Errorf("Failed updating the network configuration: %s", err)
+		}
+	}
+
+	if d.HasChange("security_group_name") {
+		old, new := d.GetChange("security_group_name")
+		oldName := old.(string)
+		newName := new.(string)
+
+		if oldName != "" {
+			log.Println("[INFO] Detaching previous network security group from subnet.")
+			reqID, err := netSecClient.RemoveNetworkSecurityGroupFromSubnet(oldName, name, vnetName)
+			if err != nil {
+				return fmt.Errorf("Error issuing network security group removal from subnet %q: %s", name, err)
+			}
+			if err := managementClient.WaitForOperation(reqID, nil); err != nil {
+				return fmt.Errorf("Error removing network security group from subnet %q: %s", name, err)
+			}
+		}
+
+		if newName != "" {
+			log.Println("[INFO] Attaching new network security group to subnet.")
+			reqID, err := netSecClient.AddNetworkSecurityToSubnet(newName, name, vnetName)
+			if err != nil {
+				return fmt.Errorf("Error issuing network security group attachment to subnet %q: %s", name, err)
+			}
+			if err := managementClient.WaitForOperation(reqID, nil); err != nil {
+				return fmt.Errorf("Error attaching network security group to subnet %q: %s", name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// resourceAzureSubnetExists does all the necessary API calls to check
+// whether the subnet still exists on Azure.
+func resourceAzureSubnetExists(d *schema.ResourceData, meta interface{}) (bool, error) {
+	azureClient, ok := meta.(*AzureClient)
+	if !ok {
+		return false, fmt.Errorf("Failed to convert to *AzureClient, got: %T", meta)
+	}
+	networkClient := virtualnetwork.NewClient(azureClient.managementClient)
+
+	name := d.Get("name").(string)
+	vnetName := d.Get("virtual_network_name").(string)
+
+	netConf, err := networkClient.GetVirtualNetworkConfiguration()
+	if err != nil {
+		return false, fmt.Errorf("Error while retrieving current network configuration: %s", err)
+	}
+
+	for _, vnet := range netConf.Configuration.VirtualNetworkSites {
+		if vnet.Name != vnetName {
+			continue
+		}
+		for _, sub := range vnet.Subnets {
+			if sub.Name == name {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// resourceAzureSubnetDelete does all the necessary API calls to remove the
+// subnet from its virtual network's configuration on Azure.
+func resourceAzureSubnetDelete(d *schema.ResourceData, meta interface{}) error {
+	azureClient, ok := meta.(*AzureClient)
+	if !ok {
+		return fmt.Errorf("Failed to convert to *AzureClient, got: %T", meta)
+	}
+	managementClient := azureClient.managementClient
+	networkClient := virtualnetwork.NewClient(managementClient)
+	netSecClient := netsecgroup.NewClient(managementClient)
+
+	name := d.Get("name").(string)
+	vnetName := d.Get("virtual_network_name").(string)
+	secGroupName := d.Get("security_group_name").(string)
+
+	if secGroupName != "" {
+		log.Println("[INFO] Detaching network security group before subnet deletion.")
+		reqID, err := netSecClient.RemoveNetworkSecurityGroupFromSubnet(secGroupName, name, vnetName)
+		if err != nil {
+			return fmt.Errorf("Error issuing network security group removal from subnet %q: %s", name, err)
+		}
+		if err := managementClient.WaitForOperation(reqID, nil); err != nil {
+			return fmt.Errorf("Error removing network security group from subnet %q: %s", name, err)
+		}
+	}
+
+	log.Println("[INFO] Retrieving current network configuration from Azure.")
+	azureClient.mutex.Lock()
+	defer azureClient.mutex.Unlock()
+	netConf, err := networkClient.GetVirtualNetworkConfiguration()
+	if err != nil {
+		return fmt.Errorf("Error while retrieving current network configuration: %s", err)
+	}
+
+	for i, vnet := range netConf.Configuration.VirtualNetworkSites {
+		if vnet.Name != vnetName {
+			continue
+		}
+		for j, sub := range vnet.Subnets {
+			if sub.Name == name {
+				netConf.Configuration.VirtualNetworkSites[i].Subnets = append(
+					vnet.Subnets[:j],
+					vnet.Subnets[j+1:]...,
+				)
+			}
+		}
+	}
+
+	log.Println("[INFO] Sending updated network configuration back to Azure.")
+	reqID, err := networkClient.SetVirtualNetworkConfiguration(netConf)
+	if err != nil {
+		return fmt.Errorf("Failed updating network configuration: %s", err)
+	}
+	if err := managementClient.WaitForOperation(reqID, nil); err != nil {
+		return fmt.Errorf("Failed updating the network configuration: %s", err)
+	}
+
+	d.SetId("")
+	return nil
+}
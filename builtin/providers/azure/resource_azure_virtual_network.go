@@ -3,11 +3,16 @@ package azure
 import (
 	"fmt"
 	"log"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/management"
 	netsecgroup "github.com/Azure/azure-sdk-for-go/management/networksecuritygroup"
 	"github.com/Azure/azure-sdk-for-go/management/virtualnetwork"
+	"github.com/hashicorp/terraform/helper/azureid"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
 )
 
 // resourceAzureVirtualNetwork returns the schema.Resource associated to an
@@ -21,6 +26,7 @@ func resourceAzureVirtualNetwork() *schema.Resource {
 		Delete: resourceAzureVirtualNetworkDelete,
 
 		SchemaVersion: 1,
+		MigrateState:  resourceAzureVirtualNetworkMigrateState,
 
 		Schema: map[string]*schema.Schema{
 			"name": &schema.Schema{
@@ -43,8 +49,9 @@ func resourceAzureVirtualNetwork() *schema.Resource {
 				},
 			},
 			"subnet": &schema.Schema{
-				Type:     schema.TypeList,
-				Optional: true,
+				Type:       schema.TypeList,
+				Optional:   true,
+				Deprecated: "Use the standalone azure_subnet resource instead, which manages subnets without racing on the shared network configuration.",
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"name": &schema.Schema{
@@ -75,6 +82,74 @@ func resourceAzureVirtualNetwork() *schema.Resource {
 	}
 }
 
+// resourceAzureVirtualNetworkMigrateState upgrades the on-disk state of a
+// resourceAzureVirtualNetwork from an older schema version to the current
+// one, dispatching on the recorded source version.
+func resourceAzureVirtualNetworkMigrateState(v int, is *terraform.InstanceState, meta interface{}) (*terraform.InstanceState, error) {
+	switch v {
+	case 0:
+		log.Println("[INFO] Found Azure Virtual Network state v0; migrating to v1.")
+		return migrateAzureVirtualNetworkStateV0toV1(is)
+	default:
+		return is, fmt.Errorf("Unexpected schema version for Azure Virtual Network state: %d", v)
+	}
+}
+
+// migrateAzureVirtualNetworkStateV0toV1 translates the attributes of the
+// pre-1.0 schema into their v1 equivalents:
+//   - the 'label' attribute becomes the resource's ID;
+//   - the legacy 'dns_servers.<name>' map becomes the indexed
+//     'dns_servers_names.N' list;
+//   - the legacy flat 'subnet_name'/'subnet_prefix'/'subnet_security_group_name'
+//     attributes (which only ever supported a single subnet) become the
+//     nested 'subnet.0.*' form.
+func migrateAzureVirtualNetworkStateV0toV1(is *terraform.InstanceState) (*terraform.InstanceState, error) {
+	if is == nil || len(is.Attributes) == 0 {
+		log.Println("[DEBUG] Empty Azure Virtual Network state; nothing to migrate.")
+		return is, nil
+	}
+
+	log.Printf("[DEBUG] Azure Virtual Network attributes before migration: %#v", is.Attributes)
+
+	if label, ok := is.Attributes["label"]; ok && label != "" {
+		is.ID = label
+		delete(is.Attributes, "label")
+	}
+
+	// migrate the legacy 'dns_servers' map into the indexed
+	// 'dns_servers_names' list, in alphabetical order of the old map keys
+	// for a deterministic result.
+	const dnsPrefix = "dns_servers."
+	var dnsNames []string
+	for k := range is.Attributes {
+		if strings.HasPrefix(k, dnsPrefix) && k != dnsPrefix+"#" {
+			dnsNames = append(dnsNames, strings.TrimPrefix(k, dnsPrefix))
+			delete(is.Attributes, k)
+		}
+	}
+	if len(dnsNames) > 0 {
+		sort.Strings(dnsNames)
+		is.Attributes["dns_servers_names.#"] = strconv.Itoa(len(dnsNames))
+		for i, name := range dnsNames {
+			is.Attributes[fmt.Sprintf("dns_servers_names.%d", i)] = name
+		}
+	}
+
+	// migrate the legacy single flat subnet into 'subnet.0.*'.
+	if name, ok := is.Attributes["subnet_name"]; ok && name != "" {
+		is.Attributes["subnet.#"] = "1"
+		is.Attributes["subnet.0.name"] = name
+		is.Attributes["subnet.0.prefix"] = is.Attributes["subnet_prefix"]
+		is.Attributes["subnet.0.security_group_name"] = is.Attributes["subnet_security_group_name"]
+		delete(is.Attributes, "subnet_name")
+		delete(is.Attributes, "subnet_prefix")
+		delete(is.Attributes, "subnet_security_group_name")
+	}
+
+	log.Printf("[DEBUG] Azure Virtual Network attributes after migration: %#v", is.Attributes)
+	return is, nil
+}
+
 // resourceAzureVirtualNetworkCreate does all the necessary API calls to create
 // an Azure virtual network.
 func resourceAzureVirtualNetworkCreate(d *schema.ResourceData, meta interface{}) error {
@@ -140,7 +215,7 @@ func resourceAzureVirtualNetworkCreate(d *schema.ResourceData, meta interface{})
 					if err != nil {
 						return fmt.Errorf("Failed requesting addition of network security to subnet %d: %s", i+1, err)
 					}
-					err = managementClient.WaitForOperation(reqID, nil)
+					err = azureClient.waitForOperationWithRetry(reqID)
 					if err != nil {
 						return fmt.Errorf("Failed adding network security settings to subnet %d: %s", i+1, err)
 					}
@@ -167,12 +242,12 @@ func resourceAzureVirtualNetworkCreate(d *schema.ResourceData, meta interface{})
 	if err != nil {
 		return fmt.Errorf("Failed updating network configuration: %s", err)
 	}
-	err = managementClient.WaitForOperation(reqID, nil)
+	err = azureClient.waitForOperationWithRetry(reqID)
 	if err != nil {
 		return fmt.Errorf("Failed updating the network configuration: %s", err)
 	}
 
-	d.SetId(getRandomStringLabel(50))
+	d.SetId(azureid.NewUniqueID("virtual-network"))
 	return nil
 }
 
@@ -325,7 +400,7 @@ func resourceAzureVirtualNetworkUpdate(d *schema.ResourceData, meta interface{})
 							if err != nil {
 								return fmt.Errorf("Error issuing removal security group settings from subnet %d for update: %s", i+1, err)
 							}
-							err = managementClient.WaitForOperation(reqID, nil)
+							err = azureClient.waitForOperationWithRetry(reqID)
 							if err != nil {
 								return fmt.Errorf("Error removing security group settings from subnet %d for update: %s", i+1, err)
 							}
@@ -339,7 +414,7 @@ func resourceAzureVirtualNetworkUpdate(d *schema.ResourceData, meta interface{})
 							if err != nil {
 								return fmt.Errorf("Error issuing network security group settings application for subnet %d: %s", i, err)
 							}
-							err = managementClient.WaitForOperation(reqID, nil)
+							err = azureClient.waitForOperationWithRetry(reqID)
 							if err != nil {
 								return fmt.Errorf("Error removing network security group settings for subnet %d: %s", i+1, err)
 							}
@@ -362,7 +437,7 @@ func resourceAzureVirtualNetworkUpdate(d *schema.ResourceData, meta interface{})
 		if err != nil {
 			return fmt.Errorf("Failed to issue set new Azure network configuration: %s", err)
 		}
-		err = managementClient.WaitForOperation(reqID, nil)
+		err = azureClient.waitForOperationWithRetry(reqID)
 		if err != nil {
 			return fmt.Errorf("Failed to set new Azure network configuration: %s", err)
 		}
@@ -441,7 +516,7 @@ func resourceAzureVirtualNetworkDelete(d *schema.ResourceData, meta interface{})
 				if err != nil {
 					return fmt.Errorf("Error issuing network security group removal from subnet %d: %s", i+1, err)
 				}
-				err = managementClient.WaitForOperation(reqID, nil)
+				err = azureClient.waitForOperationWithRetry(reqID)
 				if err != nil {
 					return fmt.Errorf("Error removing network security group settings from subnet %d: %s", i+1, err)
 				}
@@ -472,7 +547,7 @@ func resourceAzureVirtualNetworkDelete(d *schema.ResourceData, meta interface{})
 		if err != nil {
 			return fmt.Errorf("Failed updating network configuration: %s", err)
 		}
-		err = managementClient.WaitForOperation(reqID, nil)
+		err = azureClient.waitForOperationWithRetry(reqID)
 		if err != nil {
 			return fmt.Errorf("Failed to set new Azure network configuration: %s", err)
 		}
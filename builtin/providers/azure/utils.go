@@ -1,7 +1,6 @@
 package azure
 
 import (
-	"math/rand"
 	"strings"
 )
 
@@ -15,17 +14,6 @@ func reverseDNSName(dnsName string) string {
 	return strings.Join(bits, ".")
 }
 
-// getRandomStringLabel returns a random string of the given length.
-func getRandomStringLabel(n int) string {
-	var chars = []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789")
-
-	buf := make([]rune, n)
-	for i := 0; i < n; i++ {
-		buf[i] = chars[rand.Intn(len(chars))]
-	}
-	return string(buf)
-}
-
 // sprintfParams is a helper function which takes a string-bool map and returns
 // a formatted string with all the keys for displaying in errors.
 func sprintfParams(m map[string]bool) string {
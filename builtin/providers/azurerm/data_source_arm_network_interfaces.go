@@ -0,0 +1,260 @@
+package azurerm
+
+import (
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/arm/compute"
+	"github.com/Azure/azure-sdk-for-go/arm/network"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// dataSourceArmNetworkInterfaces returns the *schema.Resource associated to
+// the azurerm_network_interfaces data source, used for discovering NICs
+// across a subscription by resource group, location and/or tag.
+func dataSourceArmNetworkInterfaces() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceArmNetworkInterfacesRead,
+
+		Schema: map[string]*schema.Schema{
+			"resource_group_name": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			"location": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			"tag": &schema.Schema{
+				Type:     schema.TypeMap,
+				Optional: true,
+			},
+
+			"network_interfaces": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"resource_group_name": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"location": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"mac_address": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"vm_id": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"network_security_group_id": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"application_security_group_ids": &schema.Schema{
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"private_ip_addresses": &schema.Schema{
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"public_ip_ids": &schema.Schema{
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"tags": &schema.Schema{
+							Type:     schema.TypeMap,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// dataSourceArmNetworkInterfacesRead pages through every NIC visible to the
+// provider (optionally scoped to a resource group), filters them by the
+// given location/tag criteria, and resolves the owning VM's tags for each.
+func dataSourceArmNetworkInterfacesRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*ArmClient)
+	ifaceClient := client.ifaceClient
+	vmClient := client.vmClient
+
+	resGrp := d.Get("resource_group_name").(string)
+	location := d.Get("location").(string)
+	tagFilter := d.Get("tag").(map[string]interface{})
+
+	var ifaces []network.Interface
+	if resGrp != "" {
+		resp, err := ifaceClient.List(resGrp)
+		if err != nil {
+			return fmt.Errorf("Error listing network interfaces in resource group %q: %s", resGrp, err)
+		}
+		for resp.Value != nil {
+			ifaces = append(ifaces, *resp.Value...)
+			if resp.NextLink == nil || *resp.NextLink == "" {
+				break
+			}
+			resp, err = ifaceClient.ListNextResults(resp)
+			if err != nil {
+				return fmt.Errorf("Error paging network interfaces in resource group %q: %s", resGrp, err)
+			}
+		}
+	} else {
+		resp, err := ifaceClient.ListAll()
+		if err != nil {
+			return fmt.Errorf("Error listing network interfaces: %s", err)
+		}
+		for resp.Value != nil {
+			ifaces = append(ifaces, *resp.Value...)
+			if resp.NextLink == nil || *resp.NextLink == "" {
+				break
+			}
+			resp, err = ifaceClient.ListAllNextResults(resp)
+			if err != nil {
+				return fmt.Errorf("Error paging network interfaces: %s", err)
+			}
+		}
+	}
+
+	var results []map[string]interface{}
+	for _, iface := range ifaces {
+		if location != "" && iface.Location != nil && *iface.Location != location {
+			continue
+		}
+
+		tags := map[string]string{}
+		if iface.Tags != nil {
+			for k, v := range *iface.Tags {
+				tags[k] = *v
+			}
+		}
+
+		// resolve the owning VM's tags before filtering, since NICs don't
+		// carry their own copy of the VM's inventory metadata and a filter
+		// may only match a tag set on the VM.
+		if props := iface.Properties; props != nil && props.VirtualMachine != nil {
+			vmTags, err := resolveVirtualMachineTags(vmClient, *props.VirtualMachine.ID)
+			if err != nil {
+				return fmt.Errorf("Error resolving tags for VM owning interface %q: %s", *iface.Name, err)
+			}
+			for k, val := range vmTags {
+				tags[k] = val
+			}
+		}
+
+		if !matchesTagFilter(tags, tagFilter) {
+			continue
+		}
+
+		id, err := parseAzureResourceID(*iface.ID)
+		if err != nil {
+			return fmt.Errorf("Error parsing ID of network interface %q: %s", *iface.Name, err)
+		}
+
+		v := map[string]interface{}{
+			"name":                iface.Name,
+			"resource_group_name": id.ResourceGroup,
+			"location":            iface.Location,
+		}
+
+		if props := iface.Properties; props != nil {
+			v["mac_address"] = props.MacAddress
+
+			if props.VirtualMachine != nil {
+				v["vm_id"] = *props.VirtualMachine.ID
+			}
+
+			if props.NetworkSecurityGroup != nil {
+				v["network_security_group_id"] = *props.NetworkSecurityGroup.ID
+			}
+
+			var asgIDs []string
+			var privateIPs []string
+			var pubIPIDs []string
+			if props.IPConfigurations != nil {
+				for _, ipconf := range *props.IPConfigurations {
+					if ipconf.Properties == nil {
+						continue
+					}
+					if ipconf.Properties.PrivateIPAddress != nil {
+						privateIPs = append(privateIPs, *ipconf.Properties.PrivateIPAddress)
+					}
+					if ipconf.Properties.PublicIPAddress != nil {
+						pubIPIDs = append(pubIPIDs, *ipconf.Properties.PublicIPAddress.ID)
+					}
+					if ipconf.Properties.ApplicationSecurityGroups != nil {
+						for _, asg := range *ipconf.Properties.ApplicationSecurityGroups {
+							asgIDs = append(asgIDs, *asg.ID)
+						}
+					}
+				}
+			}
+			v["application_security_group_ids"] = asgIDs
+			v["private_ip_addresses"] = privateIPs
+			v["public_ip_ids"] = pubIPIDs
+		}
+		v["tags"] = tags
+
+		results = append(results, v)
+	}
+
+	d.SetId(fmt.Sprintf("network-interfaces-%s-%s", resGrp, location))
+	d.Set("network_interfaces", results)
+
+	return nil
+}
+
+// matchesTagFilter returns true if every key/value pair in filter is present
+// in tags. An empty filter matches everything.
+func matchesTagFilter(tags map[string]string, filter map[string]interface{}) bool {
+	for k, v := range filter {
+		if tags[k] != v.(string) {
+			return false
+		}
+	}
+	return true
+}
+
+// resolveVirtualMachineTags looks up the tags of the VM the given ARM
+// resource ID points to.
+func resolveVirtualMachineTags(vmClient compute.VirtualMachinesClient, vmID string) (map[string]string, error) {
+	id, err := parseAzureResourceID(vmID)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing ID of virtual machine %q: %s", vmID, err)
+	}
+
+	vm, err := vmClient.Get(id.ResourceGroup, id.Path["virtualMachines"], "")
+	if err != nil {
+		return nil, fmt.Errorf("Error reading virtual machine %q off Azure: %s", vmID, err)
+	}
+
+	tags := map[string]string{}
+	if vm.Tags != nil {
+		for k, v := range *vm.Tags {
+			tags[k] = *v
+		}
+	}
+
+	return tags, nil
+}
@@ -0,0 +1,144 @@
+package azurerm
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/arm/dns"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// resourceArmDnsARecord returns the *schema.Resource associated to an
+// A record within an azurerm_dns_zone.
+func resourceArmDnsARecord() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceArmDnsARecordCreateUpdate,
+		Read:   resourceArmDnsARecordRead,
+		Update: resourceArmDnsARecordCreateUpdate,
+		Delete: resourceArmDnsARecordDelete,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"resource_group_name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"zone_name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"ttl": &schema.Schema{
+				Type:     schema.TypeInt,
+				Required: true,
+			},
+
+			"records": &schema.Schema{
+				Type:     schema.TypeList,
+				Required: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+// resourceArmDnsARecordCreateUpdate creates or updates the given A record on ARM.
+func resourceArmDnsARecordCreateUpdate(d *schema.ResourceData, meta interface{}) error {
+	recordSetsClient := meta.(*ArmClient).dnsRecordSetsClient
+
+	name := d.Get("name").(string)
+	resGrp := d.Get("resource_group_name").(string)
+	zoneName := d.Get("zone_name").(string)
+	ttl := int64(d.Get("ttl").(int))
+
+	var records []dns.ARecord
+	for _, ip := range d.Get("records").([]interface{}) {
+		addr := ip.(string)
+		records = append(records, dns.ARecord{Ipv4Address: &addr})
+	}
+
+	resp, err := recordSetsClient.CreateOrUpdate(
+		resGrp, zoneName, name, dns.A,
+		dns.RecordSet{
+			RecordSetProperties: &dns.RecordSetProperties{
+				TTL:      &ttl,
+				ARecords: &records,
+			},
+		}, "", "")
+	if err != nil {
+		return fmt.Errorf("Error creating/updating DNS A record %q: %s", name, err)
+	}
+
+	d.SetId(*resp.ID)
+
+	return resourceArmDnsARecordRead(d, meta)
+}
+
+// resourceArmDnsARecordRead reads back the state of an A record off ARM.
+func resourceArmDnsARecordRead(d *schema.ResourceData, meta interface{}) error {
+	recordSetsClient := meta.(*ArmClient).dnsRecordSetsClient
+
+	id, err := parseAzureResourceID(d.Id())
+	if err != nil {
+		return fmt.Errorf("Error parsing ID of DNS A record: %s", err)
+	}
+	resGrp := id.ResourceGroup
+	zoneName := id.Path["dnszones"]
+	name := id.Path["A"]
+
+	resp, err := recordSetsClient.Get(resGrp, zoneName, name, dns.A)
+	if resp.StatusCode == http.StatusNotFound {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("Error reading DNS A record %q off Azure: %s", name, err)
+	}
+
+	d.Set("name", resp.Name)
+	d.Set("resource_group_name", resGrp)
+	d.Set("zone_name", zoneName)
+	if props := resp.RecordSetProperties; props != nil {
+		d.Set("ttl", props.TTL)
+
+		var records []string
+		if props.ARecords != nil {
+			for _, r := range *props.ARecords {
+				records = append(records, *r.Ipv4Address)
+			}
+		}
+		d.Set("records", records)
+	}
+
+	return nil
+}
+
+// resourceArmDnsARecordDelete deletes the given A record off ARM.
+func resourceArmDnsARecordDelete(d *schema.ResourceData, meta interface{}) error {
+	recordSetsClient := meta.(*ArmClient).dnsRecordSetsClient
+
+	id, err := parseAzureResourceID(d.Id())
+	if err != nil {
+		return fmt.Errorf("Error parsing ID of DNS A record: %s", err)
+	}
+	resGrp := id.ResourceGroup
+	zoneName := id.Path["dnszones"]
+	name := id.Path["A"]
+
+	_, err = recordSetsClient.Delete(resGrp, zoneName, name, dns.A, "")
+	if err != nil {
+		return fmt.Errorf("Error deleting DNS A record %q: %s", name, err)
+	}
+
+	return nil
+}
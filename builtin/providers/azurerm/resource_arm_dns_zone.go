@@ -0,0 +1,122 @@
+package azurerm
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/arm/dns"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// resourceArmDnsZone returns the *schema.Resource associated to a public
+// Azure DNS zone.
+func resourceArmDnsZone() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceArmDnsZoneCreateUpdate,
+		Read:   resourceArmDnsZoneRead,
+		Update: resourceArmDnsZoneCreateUpdate,
+		Delete: resourceArmDnsZoneDelete,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"resource_group_name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"number_of_record_sets": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+
+			"max_number_of_record_sets": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+
+			"name_servers": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+// resourceArmDnsZoneCreateUpdate creates or updates the given DNS zone on ARM.
+func resourceArmDnsZoneCreateUpdate(d *schema.ResourceData, meta interface{}) error {
+	zonesClient := meta.(*ArmClient).zonesClient
+
+	name := d.Get("name").(string)
+	resGrp := d.Get("resource_group_name").(string)
+
+	location := "global"
+	resp, err := zonesClient.CreateOrUpdate(resGrp, name, dns.Zone{
+		Location: &location,
+	})
+	if err != nil {
+		return fmt.Errorf("Error creating/updating DNS zone %q: %s", name, err)
+	}
+
+	d.SetId(*resp.ID)
+
+	return resourceArmDnsZoneRead(d, meta)
+}
+
+// resourceArmDnsZoneRead reads back the state of a DNS zone off ARM.
+func resourceArmDnsZoneRead(d *schema.ResourceData, meta interface{}) error {
+	zonesClient := meta.(*ArmClient).zonesClient
+
+	id, err := parseAzureResourceID(d.Id())
+	if err != nil {
+		return fmt.Errorf("Error parsing ID of DNS zone: %s", err)
+	}
+	resGrp := id.ResourceGroup
+	name := id.Path["dnszones"]
+
+	resp, err := zonesClient.Get(resGrp, name)
+	if resp.StatusCode == http.StatusNotFound {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("Error reading DNS zone %q off Azure: %s", name, err)
+	}
+
+	d.Set("name", resp.Name)
+	d.Set("resource_group_name", resGrp)
+	if props := resp.ZoneProperties; props != nil {
+		d.Set("number_of_record_sets", props.NumberOfRecordSets)
+		d.Set("max_number_of_record_sets", props.MaxNumberOfRecordSets)
+		d.Set("name_servers", props.NameServers)
+	}
+
+	return nil
+}
+
+// resourceArmDnsZoneDelete deletes the given DNS zone off ARM.
+func resourceArmDnsZoneDelete(d *schema.ResourceData, meta interface{}) error {
+	zonesClient := meta.(*ArmClient).zonesClient
+
+	id, err := parseAzureResourceID(d.Id())
+	if err != nil {
+		return fmt.Errorf("Error parsing ID of DNS zone: %s", err)
+	}
+	resGrp := id.ResourceGroup
+	name := id.Path["dnszones"]
+
+	_, err = zonesClient.Delete(resGrp, name)
+	if err != nil {
+		return fmt.Errorf("Error deleting DNS zone %q: %s", name, err)
+	}
+
+	return nil
+}
@@ -80,6 +80,15 @@ func resourceArmNetworkInterface() *schema.Resource {
 							Default:       true,
 							ConflictsWith: []string{"private_ip_address"},
 						},
+						"private_ip_address_version": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+							Default:  string(network.IPv4),
+						},
+						"primary": &schema.Schema{
+							Type:     schema.TypeBool,
+							Optional: true,
+						},
 						"subnet_id": &schema.Schema{
 							Type:     schema.TypeString,
 							Required: true,
@@ -88,6 +97,27 @@ func resourceArmNetworkInterface() *schema.Resource {
 							Type:     schema.TypeString,
 							Optional: true,
 						},
+						"application_security_group_ids": &schema.Schema{
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"load_balancer_backend_address_pool_ids": &schema.Schema{
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"load_balancer_inbound_nat_rule_ids": &schema.Schema{
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
 					},
 				},
 			},
@@ -117,6 +147,21 @@ func resourceArmNetworkInterface() *schema.Resource {
 				Type:     schema.TypeString,
 				Optional: true,
 			},
+
+			"internal_domain_name_suffix": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			"enable_accelerated_networking": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+
+			"enable_ip_forwarding": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
 		},
 	}
 }
@@ -130,7 +175,10 @@ func resourceArmNetworkInterfaceCreate(d *schema.ResourceData, meta interface{})
 	resGrp := d.Get("resource_group_name").(string)
 	vmId := d.Get("vm_id").(string)
 
-	fields := getStringFields(d, []string{"network_security_group_id", "internal_name", "internal_fqdn"})
+	fields := getStringFields(d, []string{"network_security_group_id", "internal_name", "internal_fqdn", "internal_domain_name_suffix"})
+
+	accelNet := d.Get("enable_accelerated_networking").(bool)
+	ipForwarding := d.Get("enable_ip_forwarding").(bool)
 
 	// get dns servers:
 	var dnses []string
@@ -174,13 +222,41 @@ func resourceArmNetworkInterfaceCreate(d *schema.ResourceData, meta interface{})
 				pubip = v.(string)
 			}
 
+			primary := conf["primary"].(bool)
+			ipVersion := network.IPVersion(conf["private_ip_address_version"].(string))
+
+			// get the application security groups this ip_config should be a member of:
+			var asgs []network.ApplicationSecurityGroup
+			for _, id := range conf["application_security_group_ids"].([]interface{}) {
+				asgID := id.(string)
+				asgs = append(asgs, network.ApplicationSecurityGroup{ID: &asgID})
+			}
+
+			// get the load balancer backend pools/nat rules this ip_config should be a member of:
+			var lbPools []network.BackendAddressPool
+			for _, id := range conf["load_balancer_backend_address_pool_ids"].([]interface{}) {
+				poolID := id.(string)
+				lbPools = append(lbPools, network.BackendAddressPool{ID: &poolID})
+			}
+
+			var natRules []network.InboundNatRule
+			for _, id := range conf["load_balancer_inbound_nat_rule_ids"].([]interface{}) {
+				ruleID := id.(string)
+				natRules = append(natRules, network.InboundNatRule{ID: &ruleID})
+			}
+
 			ipconfigs = append(ipconfigs, network.InterfaceIPConfiguration{
 				Name: &name,
 				Properties: &network.InterfaceIPConfigurationPropertiesFormat{
-					PrivateIPAddress:          &addr,
-					PrivateIPAllocationMethod: allocMeth,
-					Subnet:          &network.SubResource{&sub},
-					PublicIPAddress: &network.SubResource{&pubip},
+					PrivateIPAddress:                &addr,
+					PrivateIPAddressVersion:         ipVersion,
+					PrivateIPAllocationMethod:       allocMeth,
+					Primary:                         &primary,
+					Subnet:                          &network.SubResource{&sub},
+					PublicIPAddress:                 &network.SubResource{&pubip},
+					ApplicationSecurityGroups:       &asgs,
+					LoadBalancerBackendAddressPools: &lbPools,
+					LoadBalancerInboundNatRules:     &natRules,
 				},
 			})
 		}
@@ -190,12 +266,15 @@ func resourceArmNetworkInterfaceCreate(d *schema.ResourceData, meta interface{})
 		Name:     &name,
 		Location: &location,
 		Properties: &network.InterfacePropertiesFormat{
-			VirtualMachine: &network.SubResource{&vmId},
+			VirtualMachine:              &network.SubResource{&vmId},
+			EnableAcceleratedNetworking: &accelNet,
+			EnableIPForwarding:          &ipForwarding,
 			DNSSettings: &network.InterfaceDNSSettings{
-				DNSServers:           &dnses,
-				AppliedDNSServers:    &usedDnses,
-				InternalDNSNameLabel: fields["internal_name"],
-				InternalFqdn:         fields["internal_fqdn"],
+				DNSServers:               &dnses,
+				AppliedDNSServers:        &usedDnses,
+				InternalDNSNameLabel:     fields["internal_name"],
+				InternalFqdn:             fields["internal_fqdn"],
+				InternalDomainNameSuffix: fields["internal_domain_name_suffix"],
 			},
 		},
 	})
@@ -250,6 +329,13 @@ func resourceArmNetworkInterfaceRead(d *schema.ResourceData, meta interface{}) e
 	d.Set("network_security_group_id", *props.NetworkSecurityGroup.ID)
 	d.Set("mac_address", *props.MacAddress)
 
+	if props.EnableAcceleratedNetworking != nil {
+		d.Set("enable_accelerated_networking", *props.EnableAcceleratedNetworking)
+	}
+	if props.EnableIPForwarding != nil {
+		d.Set("enable_ip_forwarding", *props.EnableIPForwarding)
+	}
+
 	// get the ip configs:
 	var ipConfigs []map[string]interface{}
 	for _, ipconf := range *props.IPConfigurations {
@@ -269,6 +355,35 @@ func resourceArmNetworkInterfaceRead(d *schema.ResourceData, meta interface{}) e
 
 		v["subnet_id"] = *ipconf.Properties.Subnet.ID
 		v["public_ip_id"] = *ipconf.Properties.PublicIPAddress.ID
+		v["private_ip_address_version"] = string(ipconf.Properties.PrivateIPAddressVersion)
+
+		if ipconf.Properties.Primary != nil {
+			v["primary"] = *ipconf.Properties.Primary
+		}
+
+		var asgIDs []string
+		if ipconf.Properties.ApplicationSecurityGroups != nil {
+			for _, asg := range *ipconf.Properties.ApplicationSecurityGroups {
+				asgIDs = append(asgIDs, *asg.ID)
+			}
+		}
+		v["application_security_group_ids"] = asgIDs
+
+		var lbPoolIDs []string
+		if ipconf.Properties.LoadBalancerBackendAddressPools != nil {
+			for _, pool := range *ipconf.Properties.LoadBalancerBackendAddressPools {
+				lbPoolIDs = append(lbPoolIDs, *pool.ID)
+			}
+		}
+		v["load_balancer_backend_address_pool_ids"] = lbPoolIDs
+
+		var natRuleIDs []string
+		if ipconf.Properties.LoadBalancerInboundNatRules != nil {
+			for _, rule := range *ipconf.Properties.LoadBalancerInboundNatRules {
+				natRuleIDs = append(natRuleIDs, *rule.ID)
+			}
+		}
+		v["load_balancer_inbound_nat_rule_ids"] = natRuleIDs
 
 		ipConfigs = append(ipConfigs, v)
 	}
@@ -279,6 +394,9 @@ func resourceArmNetworkInterfaceRead(d *schema.ResourceData, meta interface{}) e
 	d.Set("applied_dns_servers", *iface.Properties.DNSSettings.AppliedDNSServers)
 	d.Set("internal_name", *iface.Properties.DNSSettings.InternalDNSNameLabel)
 	d.Set("internal_fqdn", *iface.Properties.DNSSettings.InternalFqdn)
+	if iface.Properties.DNSSettings.InternalDomainNameSuffix != nil {
+		d.Set("internal_domain_name_suffix", *iface.Properties.DNSSettings.InternalDomainNameSuffix)
+	}
 
 	return nil
 }
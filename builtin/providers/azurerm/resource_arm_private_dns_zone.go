@@ -0,0 +1,114 @@
+package azurerm
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/arm/privatedns"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// resourceArmPrivateDnsZone returns the *schema.Resource associated to a
+// privately-scoped Azure DNS zone.
+func resourceArmPrivateDnsZone() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceArmPrivateDnsZoneCreateUpdate,
+		Read:   resourceArmPrivateDnsZoneRead,
+		Update: resourceArmPrivateDnsZoneCreateUpdate,
+		Delete: resourceArmPrivateDnsZoneDelete,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"resource_group_name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"number_of_record_sets": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+
+			"number_of_virtual_network_links": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// resourceArmPrivateDnsZoneCreateUpdate creates or updates the given private
+// DNS zone on ARM.
+func resourceArmPrivateDnsZoneCreateUpdate(d *schema.ResourceData, meta interface{}) error {
+	zonesClient := meta.(*ArmClient).privateZonesClient
+
+	name := d.Get("name").(string)
+	resGrp := d.Get("resource_group_name").(string)
+	location := "global"
+
+	resp, err := zonesClient.CreateOrUpdate(resGrp, name, privatedns.PrivateZone{
+		Location: &location,
+	})
+	if err != nil {
+		return fmt.Errorf("Error creating/updating private DNS zone %q: %s", name, err)
+	}
+
+	d.SetId(*resp.ID)
+
+	return resourceArmPrivateDnsZoneRead(d, meta)
+}
+
+// resourceArmPrivateDnsZoneRead reads back the state of a private DNS zone off ARM.
+func resourceArmPrivateDnsZoneRead(d *schema.ResourceData, meta interface{}) error {
+	zonesClient := meta.(*ArmClient).privateZonesClient
+
+	id, err := parseAzureResourceID(d.Id())
+	if err != nil {
+		return fmt.Errorf("Error parsing ID of private DNS zone: %s", err)
+	}
+	resGrp := id.ResourceGroup
+	name := id.Path["privateDnsZones"]
+
+	resp, err := zonesClient.Get(resGrp, name)
+	if resp.StatusCode == http.StatusNotFound {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("Error reading private DNS zone %q off Azure: %s", name, err)
+	}
+
+	d.Set("name", resp.Name)
+	d.Set("resource_group_name", resGrp)
+	if props := resp.PrivateZoneProperties; props != nil {
+		d.Set("number_of_record_sets", props.NumberOfRecordSets)
+		d.Set("number_of_virtual_network_links", props.NumberOfVirtualNetworkLinks)
+	}
+
+	return nil
+}
+
+// resourceArmPrivateDnsZoneDelete deletes the given private DNS zone off ARM.
+func resourceArmPrivateDnsZoneDelete(d *schema.ResourceData, meta interface{}) error {
+	zonesClient := meta.(*ArmClient).privateZonesClient
+
+	id, err := parseAzureResourceID(d.Id())
+	if err != nil {
+		return fmt.Errorf("Error parsing ID of private DNS zone: %s", err)
+	}
+	resGrp := id.ResourceGroup
+	name := id.Path["privateDnsZones"]
+
+	_, err = zonesClient.Delete(resGrp, name)
+	if err != nil {
+		return fmt.Errorf("Error deleting private DNS zone %q: %s", name, err)
+	}
+
+	return nil
+}
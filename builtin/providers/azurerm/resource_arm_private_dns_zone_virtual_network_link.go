@@ -0,0 +1,140 @@
+package azurerm
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/arm/privatedns"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// resourceArmPrivateDnsZoneVirtualNetworkLink returns the *schema.Resource
+// associated to a link between a private DNS zone and a virtual network.
+func resourceArmPrivateDnsZoneVirtualNetworkLink() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceArmPrivateDnsZoneVirtualNetworkLinkCreateUpdate,
+		Read:   resourceArmPrivateDnsZoneVirtualNetworkLinkRead,
+		Update: resourceArmPrivateDnsZoneVirtualNetworkLinkCreateUpdate,
+		Delete: resourceArmPrivateDnsZoneVirtualNetworkLinkDelete,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"resource_group_name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"private_dns_zone_name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"virtual_network_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"registration_enabled": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+		},
+	}
+}
+
+// resourceArmPrivateDnsZoneVirtualNetworkLinkCreateUpdate creates or updates
+// the given vnet link on ARM.
+func resourceArmPrivateDnsZoneVirtualNetworkLinkCreateUpdate(d *schema.ResourceData, meta interface{}) error {
+	linksClient := meta.(*ArmClient).privateDnsVnetLinksClient
+
+	name := d.Get("name").(string)
+	resGrp := d.Get("resource_group_name").(string)
+	zoneName := d.Get("private_dns_zone_name").(string)
+	vnetID := d.Get("virtual_network_id").(string)
+	registrationEnabled := d.Get("registration_enabled").(bool)
+	location := "global"
+
+	resp, err := linksClient.CreateOrUpdate(resGrp, zoneName, name, privatedns.VirtualNetworkLink{
+		Location: &location,
+		VirtualNetworkLinkProperties: &privatedns.VirtualNetworkLinkProperties{
+			VirtualNetwork: &privatedns.SubResource{
+				ID: &vnetID,
+			},
+			RegistrationEnabled: &registrationEnabled,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("Error creating/updating private DNS zone virtual network link %q: %s", name, err)
+	}
+
+	d.SetId(*resp.ID)
+
+	return resourceArmPrivateDnsZoneVirtualNetworkLinkRead(d, meta)
+}
+
+// resourceArmPrivateDnsZoneVirtualNetworkLinkRead reads back the state of a
+// vnet link off ARM.
+func resourceArmPrivateDnsZoneVirtualNetworkLinkRead(d *schema.ResourceData, meta interface{}) error {
+	linksClient := meta.(*ArmClient).privateDnsVnetLinksClient
+
+	id, err := parseAzureResourceID(d.Id())
+	if err != nil {
+		return fmt.Errorf("Error parsing ID of private DNS zone virtual network link: %s", err)
+	}
+	resGrp := id.ResourceGroup
+	zoneName := id.Path["privateDnsZones"]
+	name := id.Path["virtualNetworkLinks"]
+
+	resp, err := linksClient.Get(resGrp, zoneName, name)
+	if resp.StatusCode == http.StatusNotFound {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("Error reading private DNS zone virtual network link %q off Azure: %s", name, err)
+	}
+
+	d.Set("name", resp.Name)
+	d.Set("resource_group_name", resGrp)
+	d.Set("private_dns_zone_name", zoneName)
+	if props := resp.VirtualNetworkLinkProperties; props != nil {
+		if props.VirtualNetwork != nil {
+			d.Set("virtual_network_id", *props.VirtualNetwork.ID)
+		}
+		if props.RegistrationEnabled != nil {
+			d.Set("registration_enabled", *props.RegistrationEnabled)
+		}
+	}
+
+	return nil
+}
+
+// resourceArmPrivateDnsZoneVirtualNetworkLinkDelete deletes the given vnet
+// link off ARM.
+func resourceArmPrivateDnsZoneVirtualNetworkLinkDelete(d *schema.ResourceData, meta interface{}) error {
+	linksClient := meta.(*ArmClient).privateDnsVnetLinksClient
+
+	id, err := parseAzureResourceID(d.Id())
+	if err != nil {
+		return fmt.Errorf("Error parsing ID of private DNS zone virtual network link: %s", err)
+	}
+	resGrp := id.ResourceGroup
+	zoneName := id.Path["privateDnsZones"]
+	name := id.Path["virtualNetworkLinks"]
+
+	_, err = linksClient.Delete(resGrp, zoneName, name)
+	if err != nil {
+		return fmt.Errorf("Error deleting private DNS zone virtual network link %q: %s", name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+// Package azureid provides collision-resistant identifier generation for
+// Azure resources that must synthesize their own Terraform ID, since the
+// classic (ASM) Azure API does not hand back one on its own.
+package azureid
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// NewUniqueID returns a unique identifier for a newly-created resource,
+// combining crypto/rand bytes with the current timestamp so that it is
+// both collision-resistant and unpredictable.
+func NewUniqueID(prefix string) string {
+	return fmt.Sprintf("%s-%d-%s", prefix, time.Now().UnixNano(), randomHex(16))
+}
+
+// NewDeterministicID returns an identifier derived solely from a resource's
+// natural key (e.g. its name plus some other uniquely-identifying
+// attribute), so that re-reading a drifted resource recovers the exact same
+// ID it was created with.
+func NewDeterministicID(prefix, naturalKey string) string {
+	sum := sha256.Sum256([]byte(naturalKey))
+	return fmt.Sprintf("%s-%s", prefix, hex.EncodeToString(sum[:16]))
+}
+
+// randomHex returns n random bytes hex-encoded.
+func randomHex(n int) string {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		// crypto/rand.Read only fails if the system's entropy source is
+		// unusable, which we treat as fatal rather than silently falling
+		// back to a weaker generator.
+		panic(fmt.Sprintf("azureid: failed to read random bytes: %s", err))
+	}
+	return hex.EncodeToString(buf)
+}
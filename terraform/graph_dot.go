@@ -33,11 +33,27 @@ type GraphDotOpts struct {
 
 	// Highlight Cycles
 	DrawCycles bool
+
+	// ClusterSCCs groups every non-trivial strongly connected component
+	// (i.e. every cycle) of the graph into its own dashed "cluster_sccN"
+	// subgraph, and condenses it to a single rank when computing ranks for
+	// the outer DAG, so the rank walk doesn't revisit cycle members.
+	ClusterSCCs bool
+
+	// MaxRank caps the rank depth that gets rendered. Vertices ranked
+	// deeper than MaxRank are collapsed into a single synthetic
+	// "...(N more)" node. Zero means unlimited.
+	MaxRank int
+
+	// FailOnCycle makes GraphDot return an error listing every non-trivial
+	// strongly connected component instead of rendering a dot graph, so
+	// callers like the planner can reject an unrunnable graph before doing
+	// any further work with it.
+	FailOnCycle bool
 }
 
 type GraphDotContext struct {
 	Opts        *GraphDotOpts
-	Cycles      [][]dag.Vertex
 	CurrentRank int
 }
 
@@ -46,6 +62,10 @@ type drawableVertex struct {
 	Rank   int
 }
 
+// overflowRankLabel is the name used for the synthetic vertex that
+// represents every node ranked deeper than GraphDotOpts.MaxRank.
+const overflowRankLabel = "...(%d more)"
+
 // GraphDot returns the dot formatting of a visual representation of
 // the given Terraform graph.
 func GraphDot(g *Graph, opts *GraphDotOpts) (string, error) {
@@ -55,6 +75,16 @@ func GraphDot(g *Graph, opts *GraphDotOpts) (string, error) {
 	buf.WriteString("digraph {\n")
 	buf.WriteString("\tcompound = true;\n")
 
+	// Compute strongly connected components up front so that cycle members
+	// can be condensed to a single rank and, optionally, clustered or
+	// highlighted visually.
+	sccs := stronglyConnectedComponents(g)
+	vertexSCC, nonTrivialSCCs := indexNonTrivialSCCs(g, sccs)
+
+	if opts.FailOnCycle && len(nonTrivialSCCs) > 0 {
+		return "", fmt.Errorf("graph contains %d cycle(s):\n%s", len(nonTrivialSCCs), describeSCCs(nonTrivialSCCs))
+	}
+
 	// Find and rank drawable vertices by doing a depth first walk from the nodes
 	// that rank themselves 0
 	drawableVertices := make(map[dag.Vertex]int)
@@ -70,8 +100,7 @@ func GraphDot(g *Graph, opts *GraphDotOpts) (string, error) {
 	}
 
 	ctx := &GraphDotContext{
-		Opts:   opts,
-		Cycles: g.Cycles(),
+		Opts: opts,
 	}
 
 	walk := func(v dag.Vertex, depth int) error {
@@ -97,6 +126,12 @@ func GraphDot(g *Graph, opts *GraphDotOpts) (string, error) {
 		return "", err
 	}
 
+	if opts.ClusterSCCs {
+		condenseSCCRanks(vertexSCC, nonTrivialSCCs, drawableVertices, rankedVertices)
+	}
+
+	overflow := applyMaxRank(opts.MaxRank, drawableVertices, rankedVertices)
+
 	// Now we draw each rank
 	rank := 0
 	vs := rankedVertices[rank]
@@ -112,14 +147,35 @@ func GraphDot(g *Graph, opts *GraphDotOpts) (string, error) {
 		// Sort by VertexName so the graph is consistent
 		sort.Sort(dag.ByVertexName(vs))
 
-		// Draw vertices
+		// Draw vertices, clustering the ones that belong to the same
+		// non-trivial SCC together when requested.
+		drawn := make(map[dag.Vertex]bool)
 		for _, v := range vs {
-			dn := v.(GraphNodeDotter)
-			scanner := bufio.NewScanner(strings.NewReader(
-				dn.Dot(dag.VertexName(v), ctx)))
-			for scanner.Scan() {
-				buf.WriteString("\t\t" + scanner.Text() + "\n")
+			if drawn[v] {
+				continue
 			}
+
+			sccIdx, isCycle := vertexSCC[v]
+			if opts.ClusterSCCs && isCycle {
+				members := sortedSCCMembers(nonTrivialSCCs[sccIdx])
+				buf.WriteString(fmt.Sprintf("\t\tsubgraph cluster_scc%d {\n", sccIdx))
+				buf.WriteString("\t\t\tstyle = dashed;\n")
+				buf.WriteString(fmt.Sprintf("\t\t\tlabel = \"cycle %d\";\n", sccIdx))
+				for _, m := range members {
+					dn, ok := m.(GraphNodeDotter)
+					if !ok {
+						continue
+					}
+					writeDotLines(buf, "\t\t\t", dn.Dot(dag.VertexName(m), ctx))
+					drawn[m] = true
+				}
+				buf.WriteString("\t\t}\n")
+				continue
+			}
+
+			dn := v.(GraphNodeDotter)
+			writeDotLines(buf, "\t\t", dn.Dot(dag.VertexName(v), ctx))
+			drawn[v] = true
 		}
 
 		// Close rank block; edges must come outside of it
@@ -145,28 +201,36 @@ func GraphDot(g *Graph, opts *GraphDotOpts) (string, error) {
 		vs = rankedVertices[rank]
 	}
 
+	if overflow > 0 {
+		buf.WriteString(fmt.Sprintf("\tsubgraph rank%d {\n", rank))
+		buf.WriteString("\t\trank = same;\n")
+		buf.WriteString(fmt.Sprintf("\t\t\"%s\";\n", fmt.Sprintf(overflowRankLabel, overflow)))
+		buf.WriteString("\t}\n")
+	}
+
 	if opts.DrawCycles {
-		colors := []string{"red", "green", "blue"}
-		for ci, cycle := range ctx.Cycles {
-			cycleEdges := make([]string, 0, len(cycle))
-			for i, c := range cycle {
-				// Catch the last wrapping edge of the cycle
-				if i+1 >= len(cycle) {
-					i = -1
+		var cycleEdges []string
+		for idx, scc := range nonTrivialSCCs {
+			members := sortedSCCMembers(scc)
+			for _, v := range members {
+				for _, t := range dag.AsVertexList(g.DownEdges(v)) {
+					target := t.(dag.Vertex)
+					if targetIdx, ok := vertexSCC[target]; !ok || targetIdx != idx {
+						continue
+					}
+					cycleEdges = append(cycleEdges, fmt.Sprintf(
+						"\t\"%s\" -> \"%s\" [color=red, penwidth=2.0];\n",
+						dag.VertexName(v),
+						dag.VertexName(target)))
 				}
-				cycleEdges = append(cycleEdges, fmt.Sprintf(
-					"\t\"%s\" -> \"%s\" [color=%s, penwidth=2.0];\n",
-					dag.VertexName(c),
-					dag.VertexName(cycle[i+1]),
-					colors[ci%len(colors)]))
 			}
+		}
 
-			// Sort to get consistent graph output
-			sort.Strings(cycleEdges)
+		// Sort to get consistent graph output
+		sort.Strings(cycleEdges)
 
-			for _, edge := range cycleEdges {
-				buf.WriteString(edge)
-			}
+		for _, edge := range cycleEdges {
+			buf.WriteString(edge)
 		}
 	}
 
@@ -174,3 +238,235 @@ func GraphDot(g *Graph, opts *GraphDotOpts) (string, error) {
 	buf.WriteString("}\n")
 	return buf.String(), nil
 }
+
+// writeDotLines writes each line of a node's Dot() output to buf, indented
+// by the given prefix.
+func writeDotLines(buf *bytes.Buffer, prefix, dot string) {
+	scanner := bufio.NewScanner(strings.NewReader(dot))
+	for scanner.Scan() {
+		buf.WriteString(prefix + scanner.Text() + "\n")
+	}
+}
+
+// describeSCCs renders one line per non-trivial SCC, listing its members by
+// name in sorted order, for use in the GraphDotOpts.FailOnCycle error.
+func describeSCCs(sccs [][]dag.Vertex) string {
+	lines := make([]string, 0, len(sccs))
+	for i, scc := range sccs {
+		names := make([]string, 0, len(scc))
+		for _, v := range sortedSCCMembers(scc) {
+			names = append(names, dag.VertexName(v))
+		}
+		lines = append(lines, fmt.Sprintf("  %d: %s", i, strings.Join(names, ", ")))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// sortedSCCMembers returns the members of an SCC sorted by vertex name, so
+// that cluster rendering is deterministic.
+func sortedSCCMembers(members []dag.Vertex) []dag.Vertex {
+	sorted := make([]dag.Vertex, len(members))
+	copy(sorted, members)
+	sort.Sort(dag.ByVertexName(sorted))
+	return sorted
+}
+
+// indexNonTrivialSCCs returns a map from each vertex in a cycle to the index
+// of its SCC in nonTrivial, plus the list of non-trivial SCCs themselves. An
+// SCC is non-trivial if it has more than one member, or its single member
+// has a self-loop.
+func indexNonTrivialSCCs(g *Graph, sccs [][]dag.Vertex) (map[dag.Vertex]int, [][]dag.Vertex) {
+	vertexSCC := make(map[dag.Vertex]int)
+	var nonTrivial [][]dag.Vertex
+
+	for _, scc := range sccs {
+		isCycle := len(scc) > 1
+		if !isCycle && len(scc) == 1 {
+			for _, t := range dag.AsVertexList(g.DownEdges(scc[0])) {
+				if t.(dag.Vertex) == scc[0] {
+					isCycle = true
+					break
+				}
+			}
+		}
+
+		if !isCycle {
+			continue
+		}
+
+		idx := len(nonTrivial)
+		nonTrivial = append(nonTrivial, scc)
+		for _, v := range scc {
+			vertexSCC[v] = idx
+		}
+	}
+
+	return vertexSCC, nonTrivial
+}
+
+// condenseSCCRanks forces every member of a non-trivial SCC onto the same,
+// lowest rank seen among its members, so the outer DAG's rank walk treats
+// the whole cycle as a single condensed super-node.
+func condenseSCCRanks(vertexSCC map[dag.Vertex]int, sccs [][]dag.Vertex, drawableVertices map[dag.Vertex]int, rankedVertices map[int][]dag.Vertex) {
+	for _, scc := range sccs {
+		minRank := -1
+		for _, v := range scc {
+			if rank, ok := drawableVertices[v]; ok {
+				if minRank == -1 || rank < minRank {
+					minRank = rank
+				}
+			}
+		}
+		if minRank == -1 {
+			continue
+		}
+
+		for _, v := range scc {
+			oldRank, ok := drawableVertices[v]
+			if !ok || oldRank == minRank {
+				continue
+			}
+
+			rankedVertices[oldRank] = removeVertex(rankedVertices[oldRank], v)
+			drawableVertices[v] = minRank
+			rankedVertices[minRank] = append(rankedVertices[minRank], v)
+		}
+	}
+}
+
+// removeVertex returns vs with v removed.
+func removeVertex(vs []dag.Vertex, v dag.Vertex) []dag.Vertex {
+	for i, c := range vs {
+		if c == v {
+			return append(vs[:i], vs[i+1:]...)
+		}
+	}
+	return vs
+}
+
+// applyMaxRank truncates rankedVertices/drawableVertices to the given
+// maximum rank, returning the number of vertices that were collapsed out.
+// A maxRank of zero means unlimited, and is a no-op.
+func applyMaxRank(maxRank int, drawableVertices map[dag.Vertex]int, rankedVertices map[int][]dag.Vertex) int {
+	if maxRank <= 0 {
+		return 0
+	}
+
+	overflow := 0
+	for rank, vs := range rankedVertices {
+		if rank <= maxRank {
+			continue
+		}
+		overflow += len(vs)
+		for _, v := range vs {
+			delete(drawableVertices, v)
+		}
+		delete(rankedVertices, rank)
+	}
+
+	return overflow
+}
+
+// stronglyConnectedComponents computes the strongly connected components of
+// g using an iterative version of Tarjan's algorithm (an explicit stack is
+// used in place of recursion so that large graphs can't blow the goroutine
+// stack).
+func stronglyConnectedComponents(g *Graph) [][]dag.Vertex {
+	st := &tarjanState{
+		index:   make(map[dag.Vertex]int),
+		lowlink: make(map[dag.Vertex]int),
+		onStack: make(map[dag.Vertex]bool),
+	}
+
+	for _, v := range g.Vertices() {
+		if _, visited := st.index[v]; !visited {
+			st.strongConnect(g, v)
+		}
+	}
+
+	return st.sccs
+}
+
+// tarjanState carries the bookkeeping Tarjan's algorithm needs across the
+// iterative walk: each vertex's discovery index and lowlink, whether it is
+// currently on the component stack, the component stack itself, and the
+// completed SCCs.
+type tarjanState struct {
+	indexCounter int
+	index        map[dag.Vertex]int
+	lowlink      map[dag.Vertex]int
+	onStack      map[dag.Vertex]bool
+	stack        []dag.Vertex
+	sccs         [][]dag.Vertex
+}
+
+// tarjanFrame is one level of the explicit call stack used to simulate the
+// recursive strongconnect(v) routine.
+type tarjanFrame struct {
+	v        dag.Vertex
+	children []dag.Vertex
+	i        int
+}
+
+// strongConnect runs Tarjan's strongconnect routine rooted at root, using an
+// explicit stack of tarjanFrames instead of recursing.
+func (st *tarjanState) strongConnect(g *Graph, root dag.Vertex) {
+	work := []*tarjanFrame{st.pushFrame(g, root)}
+
+	for len(work) > 0 {
+		frame := work[len(work)-1]
+		v := frame.v
+
+		if frame.i < len(frame.children) {
+			w := frame.children[frame.i]
+			frame.i++
+
+			if _, visited := st.index[w]; !visited {
+				work = append(work, st.pushFrame(g, w))
+			} else if st.onStack[w] && st.index[w] < st.lowlink[v] {
+				st.lowlink[v] = st.index[w]
+			}
+			continue
+		}
+
+		// Every successor of v has been processed; pop v's frame and
+		// propagate its lowlink to its parent, if any.
+		work = work[:len(work)-1]
+		if len(work) > 0 {
+			parent := work[len(work)-1]
+			if st.lowlink[v] < st.lowlink[parent.v] {
+				st.lowlink[parent.v] = st.lowlink[v]
+			}
+		}
+
+		if st.lowlink[v] == st.index[v] {
+			var scc []dag.Vertex
+			for {
+				w := st.stack[len(st.stack)-1]
+				st.stack = st.stack[:len(st.stack)-1]
+				st.onStack[w] = false
+				scc = append(scc, w)
+				if w == v {
+					break
+				}
+			}
+			st.sccs = append(st.sccs, scc)
+		}
+	}
+}
+
+// pushFrame assigns a fresh index/lowlink to v, pushes it onto the
+// component stack, and returns the work-stack frame used to iterate its
+// successors.
+func (st *tarjanState) pushFrame(g *Graph, v dag.Vertex) *tarjanFrame {
+	st.index[v] = st.indexCounter
+	st.lowlink[v] = st.indexCounter
+	st.indexCounter++
+	st.stack = append(st.stack, v)
+	st.onStack[v] = true
+
+	return &tarjanFrame{
+		v:        v,
+		children: dag.AsVertexList(g.DownEdges(v)),
+	}
+}
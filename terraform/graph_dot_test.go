@@ -119,12 +119,92 @@ digraph {
 		C
 	}
 	"C" -> "B";
-	"A" -> "B" [color=red, penwidth=2.0];
-	"B" -> "C" [color=red, penwidth=2.0];
-	"C" -> "A" [color=red, penwidth=2.0];
+	"A" -> "C" [color=red, penwidth=2.0];
+	"B" -> "A" [color=red, penwidth=2.0];
+	"C" -> "B" [color=red, penwidth=2.0];
 }
 			`,
 		},
+		"cluster-sccs": {
+			Opts: GraphDotOpts{
+				ClusterSCCs: true,
+			},
+			Graph: func() *Graph {
+				var g Graph
+				root := &testDrawableRanked{VertexName: "root", Rank: 0}
+				g.Add(root)
+
+				g.Add(&testDrawable{
+					VertexName:      "A",
+					DependentOnMock: []string{"root", "C"},
+				})
+
+				g.Add(&testDrawable{
+					VertexName:      "B",
+					DependentOnMock: []string{"A"},
+				})
+
+				g.Add(&testDrawable{
+					VertexName:      "C",
+					DependentOnMock: []string{"B"},
+				})
+
+				g.ConnectDependents()
+				return &g
+			},
+			Expect: `
+digraph {
+	compound = true;
+	subgraph rank0 {
+		rank = sink;
+		root
+	}
+	subgraph rank1 {
+		rank = same;
+		subgraph cluster_scc0 {
+			style = dashed;
+			label = "cycle 0";
+			A
+			B
+			C
+		}
+	}
+	"A" -> "C";
+	"A" -> "root";
+	"B" -> "A";
+	"C" -> "B";
+}
+			`,
+		},
+		"fail-on-cycle": {
+			Opts: GraphDotOpts{
+				FailOnCycle: true,
+			},
+			Graph: func() *Graph {
+				var g Graph
+				root := &testDrawableRanked{VertexName: "root", Rank: 0}
+				g.Add(root)
+
+				g.Add(&testDrawable{
+					VertexName:      "A",
+					DependentOnMock: []string{"root", "C"},
+				})
+
+				g.Add(&testDrawable{
+					VertexName:      "B",
+					DependentOnMock: []string{"A"},
+				})
+
+				g.Add(&testDrawable{
+					VertexName:      "C",
+					DependentOnMock: []string{"B"},
+				})
+
+				g.ConnectDependents()
+				return &g
+			},
+			Error: true,
+		},
 	}
 
 	for tn, tc := range cases {
@@ -132,6 +212,9 @@ digraph {
 		if (err != nil) != tc.Error {
 			t.Fatalf("%s: expected err: %t, got: %s", tn, tc.Error, err)
 		}
+		if tc.Error {
+			continue
+		}
 
 		expected := strings.TrimSpace(tc.Expect) + "\n"
 		if actual != expected {